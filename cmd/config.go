@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/hayasedb/hayase-cli/internal/models"
 	"github.com/hayasedb/hayase-cli/internal/storage"
 )
 
@@ -18,6 +21,9 @@ Examples:
   hayase-cli config                           # Show current config
   hayase-cli config set language eng-sub     # Set preferred language
   hayase-cli config set quality 720p         # Set preferred quality
+  hayase-cli config set quality best         # Always pick the highest variant
+  hayase-cli config set binge on             # Continue with the next episode
+  hayase-cli config set aniskip.mode auto    # Skip openings and endings
   hayase-cli config set provider aniworld    # Set preferred provider`,
 
 	RunE: runConfig,
@@ -30,10 +36,50 @@ var configSetCmd = &cobra.Command{
 
 Available settings:
   language    Preferred language (ger-sub, eng-sub, ger-dub)
-  quality     Preferred quality (720p, 1080p, 1440p, 2160p)  
+  languages   Fallback language order, comma separated (e.g. eng-sub,ger-dub)
+  quality     Preferred quality (360p, 480p, 720p, 1080p, 1440p, 2160p, best, worst)
+  subtitle_language  Preferred subtitle language (e.g. de, en, off, auto)
+  subtitle_forced    Only show forced subtitles, e.g. signs and songs (on, off)
+  audio_language     Preferred audio language (e.g. ja, de, auto)
+  anime.<slug>.audio_language     Audio language for one anime (empty value removes it)
+  anime.<slug>.subtitle_language  Subtitle language for one anime (e.g. en, off)
+  anime.<slug>.subtitle_forced    Forced subtitles only for one anime (on, off)
   provider    Preferred provider (aniworld)
-  player      Preferred player (mpv)
-  timeout     Request timeout in seconds`,
+  player      Preferred player (mpv, vlc)
+  mpv.mode    How mpv is run (auto, embedded, external)
+  mpv.path    mpv binary used in external mode
+  mpv.options.<name>           mpv option applied on top of the defaults (empty value drops the default)
+  mpv.profiles.<profile>.<name>  mpv option only applied when <profile> is active
+                               (mpv options live under mpv.*, not player.mpv.*, because player holds the player name)
+  mpv.profile      Active mpv option profile (empty for none)
+  mpv.vo           Video output (auto, window, terminal); auto uses the terminal without a display
+  mpv.terminal_vo  Terminal renderer (auto, kitty, sixel, tct)
+  mpv.user_config  Load mpv.conf, input.conf and scripts from the mpv config directory (on, off)
+  mpv.config_dir   mpv config directory to load instead of the default one
+  vlc.path    vlc binary (default: vlc or cvlc from PATH)
+  vlc.fullscreen   Start VLC in fullscreen (on, off)
+  binge       Play the next episode automatically (on, off)
+  binge_countdown  Seconds to wait before the next episode starts
+  prefetch    Resolve the next episode's stream during playback (on, off)
+  aniskip.mode     Skip openings and endings (off, prompt, auto); direct play cannot prompt
+                   and only lists the segments, use auto there
+  aniskip.url      AniSkip compatible API base URL
+  timeout     Request timeout in seconds
+  validate    Probe streams before playback (on, off)
+  cache       Cache resolved embeds and streams (on, off)
+  race        Resolve several hosters concurrently (on, off)
+  race_candidates  Number of hosters to race at once
+  race_grace_ms    Wait for a higher ranked hoster after the first result
+
+Examples:
+  hayase-cli config set quality 720p
+  hayase-cli config set audio_language ja
+  hayase-cli config set anime.one-piece.subtitle_language off
+  hayase-cli config set mpv.options.ao pipewire
+  hayase-cli config set mpv.options.fs no
+  hayase-cli config set mpv.profiles.laptop.hwdec no
+  hayase-cli config set mpv.profile laptop
+  hayase-cli config set mpv.user_config on`,
 
 	Args: cobra.ExactArgs(2),
 	RunE: runConfigSet,
@@ -55,9 +101,35 @@ func runConfig(*cobra.Command, []string) error {
 
 	fmt.Printf("  Provider:  %s\n", config.GetProvider())
 	fmt.Printf("  Language:  %s\n", config.GetLanguage().String())
+	fmt.Printf("  Fallback:  %s\n", formatLanguages(config.GetLanguagePriority()))
 	fmt.Printf("  Quality:   %s\n", config.GetQuality().String())
+	fmt.Printf("  Audio:     %s\n", config.GetAudioLanguage())
+	if config.GetBool("subtitle_forced") {
+		fmt.Printf("  Subtitles: %s, forced only\n", valueOr(config.GetSubtitleLanguage(), "none"))
+	} else {
+		fmt.Printf("  Subtitles: %s\n", valueOr(config.GetSubtitleLanguage(), "none"))
+	}
 	fmt.Printf("  Player:    %s\n", config.GetPlayer())
+	fmt.Printf("  MPV mode:  %s\n", config.GetString("mpv.mode"))
+	fmt.Printf("  Video out: %s\n", config.GetString("mpv.vo"))
+	if profile := config.GetMPVProfile(); profile != "" {
+		fmt.Printf("  Profile:   %s\n", profile)
+	}
+	if options := config.GetMPVOptions(); len(options) > 0 {
+		fmt.Printf("  MPV opts:  %s\n", formatOptions(options))
+	}
+	if config.GetBinge() {
+		fmt.Printf("  Binge:     on, %s countdown\n", config.GetBingeCountdown())
+	} else {
+		fmt.Printf("  Binge:     off\n")
+	}
+	fmt.Printf("  Skip:      %s\n", config.GetSkipMode())
 	fmt.Printf("  Timeout:   %d seconds\n", config.GetTimeout())
+	if config.GetBool("race") {
+		fmt.Printf("  Race:      %d hosters, %dms grace\n", config.GetInt("race_candidates"), config.GetInt("race_grace_ms"))
+	} else {
+		fmt.Printf("  Race:      off\n")
+	}
 
 	return nil
 }
@@ -79,13 +151,37 @@ func runConfigSet(_ *cobra.Command, args []string) error {
 		}
 		config.Set("language", value)
 
+	case "languages":
+		validLanguages := []string{"ger-sub", "eng-sub", "ger-dub"}
+		var languages []string
+		for _, part := range strings.Split(value, ",") {
+			lang := strings.TrimSpace(part)
+			if !contains(validLanguages, lang) {
+				return fmt.Errorf("invalid language '%s'. Valid options: %s", lang, strings.Join(validLanguages, ", "))
+			}
+			languages = append(languages, lang)
+		}
+		config.Set("languages", languages)
+
 	case "quality":
-		validQualities := []string{"720p", "1080p", "1440p", "2160p"}
+		validQualities := []string{"360p", "480p", "720p", "1080p", "1440p", "2160p", "best", "worst"}
 		if !contains(validQualities, value) {
 			return fmt.Errorf("invalid quality '%s'. Valid options: %s", value, strings.Join(validQualities, ", "))
 		}
 		config.Set("quality", value)
 
+	case "subtitle_language":
+		if value != "off" && !validLanguageCode(value) {
+			return fmt.Errorf("invalid subtitle language '%s'. Use a language code (e.g. de, en, ja), off or auto", value)
+		}
+		config.Set("subtitle_language", value)
+
+	case "audio_language":
+		if !validLanguageCode(value) {
+			return fmt.Errorf("invalid audio language '%s'. Use a language code (e.g. de, en, ja) or auto", value)
+		}
+		config.Set(key, value)
+
 	case "provider":
 		validProviders := []string{"aniworld"}
 		if !contains(validProviders, value) {
@@ -94,17 +190,96 @@ func runConfigSet(_ *cobra.Command, args []string) error {
 		config.Set("provider", value)
 
 	case "player":
-		validPlayers := []string{"mpv"}
+		validPlayers := []string{"mpv", "vlc"}
 		if !contains(validPlayers, value) {
 			return fmt.Errorf("invalid player '%s'. Valid options: %s", value, strings.Join(validPlayers, ", "))
 		}
 		config.Set("player", value)
 
+	case "mpv.mode":
+		validModes := []string{"auto", "embedded", "external"}
+		if !contains(validModes, value) {
+			return fmt.Errorf("invalid mpv mode '%s'. Valid options: %s", value, strings.Join(validModes, ", "))
+		}
+		config.Set(key, value)
+
+	case "mpv.path", "vlc.path", "mpv.config_dir":
+		config.Set(key, args[1])
+
+	case "mpv.vo":
+		validOutputs := []string{"auto", "window", "terminal"}
+		if !contains(validOutputs, value) {
+			return fmt.Errorf("invalid video output '%s'. Valid options: %s", value, strings.Join(validOutputs, ", "))
+		}
+		config.Set(key, value)
+
+	case "mpv.terminal_vo":
+		validOutputs := []string{"auto", "kitty", "sixel", "tct"}
+		if !contains(validOutputs, value) {
+			return fmt.Errorf("invalid terminal video output '%s'. Valid options: %s", value, strings.Join(validOutputs, ", "))
+		}
+		config.Set(key, value)
+
+	case "mpv.profile":
+		if value != "" && !contains(config.GetMPVProfiles(), value) {
+			return fmt.Errorf("unknown mpv profile '%s'. Define it first with: config set mpv.profiles.%s.<option> <value>", value, value)
+		}
+		config.Set(key, value)
+
+	case "aniskip.mode":
+		validModes := []string{"off", "prompt", "auto"}
+		if !contains(validModes, value) {
+			return fmt.Errorf("invalid skip mode '%s'. Valid options: %s", value, strings.Join(validModes, ", "))
+		}
+		config.Set(key, value)
+
+	case "aniskip.url":
+		if !strings.HasPrefix(value, "http://") && !strings.HasPrefix(value, "https://") {
+			return fmt.Errorf("invalid URL '%s': must start with http:// or https://", args[1])
+		}
+		config.Set(key, strings.TrimRight(args[1], "/"))
+
 	case "timeout":
 		config.Set("timeout", value)
 
+	case "binge_countdown":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid value '%s' for %s: must be zero or a positive number", value, key)
+		}
+		config.Set(key, n)
+
+	case "race", "validate", "cache", "binge", "prefetch", "mpv.user_config", "subtitle_forced", "vlc.fullscreen":
+		validValues := []string{"on", "off"}
+		if !contains(validValues, value) {
+			return fmt.Errorf("invalid value '%s'. Valid options: %s", value, strings.Join(validValues, ", "))
+		}
+		config.Set(key, value == "on")
+
+	case "race_candidates", "race_grace_ms":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid value '%s' for %s: must be a positive number", value, key)
+		}
+		config.Set(key, n)
+
 	default:
-		return fmt.Errorf("unknown configuration key '%s'", key)
+		if setting, ok := animeTrackSetting(key); ok {
+			if err := validateTrackOverride(setting, value); err != nil {
+				return err
+			}
+			if setting == "subtitle_forced" && value != "" {
+				config.Set(key, value == "on")
+			} else {
+				config.Set(key, value)
+			}
+			break
+		}
+		if !isMPVOptionKey(key) {
+			return fmt.Errorf("unknown configuration key '%s'", key)
+		}
+		config.Set(key, args[1])
+		value = args[1]
 	}
 
 	if err := config.Save(); err != nil {
@@ -116,6 +291,60 @@ func runConfigSet(_ *cobra.Command, args []string) error {
 	return nil
 }
 
+func validLanguageCode(value string) bool {
+	code := models.NormalizeLanguageCode(value)
+	return value == "auto" || (len(code) >= 2 && len(code) <= 3)
+}
+
+func animeTrackSetting(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, "anime.")
+	if !ok {
+		return "", false
+	}
+	slug, setting, found := strings.Cut(rest, ".")
+	if !found || slug == "" {
+		return "", false
+	}
+	switch setting {
+	case "audio_language", "subtitle_language", "subtitle_forced":
+		return setting, true
+	default:
+		return "", false
+	}
+}
+
+func validateTrackOverride(setting, value string) error {
+	if value == "" {
+		return nil
+	}
+	switch setting {
+	case "subtitle_forced":
+		if value != "on" && value != "off" {
+			return fmt.Errorf("invalid value '%s'. Valid options: on, off (empty to use the global setting)", value)
+		}
+	case "subtitle_language":
+		if value != "off" && (value == "auto" || !validLanguageCode(value)) {
+			return fmt.Errorf("invalid subtitle language '%s'. Use a language code (e.g. de, en, ja) or off", value)
+		}
+	default:
+		if value == "auto" || !validLanguageCode(value) {
+			return fmt.Errorf("invalid audio language '%s'. Use a language code (e.g. de, en, ja)", value)
+		}
+	}
+	return nil
+}
+
+func isMPVOptionKey(key string) bool {
+	if name, ok := strings.CutPrefix(key, "mpv.options."); ok {
+		return name != "" && !strings.Contains(name, ".")
+	}
+	if rest, ok := strings.CutPrefix(key, "mpv.profiles."); ok {
+		profile, name, found := strings.Cut(rest, ".")
+		return found && profile != "" && name != "" && !strings.Contains(name, ".")
+	}
+	return false
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -124,3 +353,20 @@ func contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+func formatLanguages(languages []models.Language) string {
+	names := make([]string, len(languages))
+	for i, lang := range languages {
+		names[i] = lang.String()
+	}
+	return strings.Join(names, " > ")
+}
+
+func formatOptions(options map[string]string) string {
+	pairs := make([]string, 0, len(options))
+	for name, value := range options {
+		pairs = append(pairs, name+"="+value)
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, " ")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hayasedb/hayase-cli/internal/players/mpv"
+	"github.com/hayasedb/hayase-cli/internal/storage"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment for playback problems",
+	Long: `Check which players can be used and explain why others cannot.
+
+Examples:
+  hayase-cli doctor`,
+
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(*cobra.Command, []string) error {
+	config, err := storage.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	playerRegistry := newPlayerRegistry(config)
+
+	fmt.Println("Players:")
+	for _, name := range playerRegistry.List() {
+		player, _ := playerRegistry.Lookup(name)
+		if err := player.CheckAvailability(); err != nil {
+			fmt.Printf("  %-6s unavailable: %v\n", name, err)
+			continue
+		}
+
+		details := ""
+		if p, ok := player.(*mpv.Player); ok {
+			details = fmt.Sprintf(" (%s mode)", p.Mode())
+			if p.UsesTerminal() {
+				details = fmt.Sprintf(" (%s mode, %s terminal output)", p.Mode(), p.TerminalOutput())
+			}
+		}
+		fmt.Printf("  %-6s ok%s\n", name, details)
+	}
+
+	fmt.Println()
+	fmt.Printf("Configured player: %s\n", config.GetPlayer())
+
+	selected, err := playerRegistry.GetDefault()
+	if err != nil {
+		fmt.Printf("Selected player:   none\n")
+		return fmt.Errorf("no player available: %w", err)
+	}
+	fmt.Printf("Selected player:   %s\n", selected.Name())
+
+	if runtime.GOOS == "linux" && os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		fmt.Println()
+		fmt.Println("Note: neither DISPLAY nor WAYLAND_DISPLAY is set, mpv renders video in the terminal unless mpv.vo is window.")
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hayasedb/hayase-cli/internal/extractors"
+	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/providers/aniworld"
+	"github.com/hayasedb/hayase-cli/internal/storage"
+)
+
+var (
+	extractJSON bool
+	extractAll  bool
+)
+
+var extractCmd = &cobra.Command{
+	Use:   "extract <redirect-or-embed-url>",
+	Short: "Debug stream extraction for a URL",
+	Long: `Follow an aniworld redirect (if given) and run the extractors on the embed URL.
+
+Prints the chain of URLs, the extractor and method that matched, headers,
+expiry and quality. Results are never read from or written to the cache.
+
+Examples:
+  hayase-cli extract https://aniworld.to/redirect/1234567
+  hayase-cli extract https://voe.sx/e/abcdef --json
+  hayase-cli extract https://example.com/e/abcdef --all`,
+
+	Args: cobra.ExactArgs(1),
+	RunE: runExtract,
+}
+
+func init() {
+	rootCmd.AddCommand(extractCmd)
+	extractCmd.Flags().BoolVar(&extractJSON, "json", false, "Print results as JSON")
+	extractCmd.Flags().BoolVar(&extractAll, "all", false, "Try every extractor, not only those claiming the URL")
+}
+
+type extractReport struct {
+	Input      string          `json:"input"`
+	EmbedURL   string          `json:"embed_url"`
+	Redirected bool            `json:"redirected"`
+	Attempts   []extractResult `json:"attempts"`
+}
+
+type extractResult struct {
+	Extractor  string            `json:"extractor"`
+	DurationMs int64             `json:"duration_ms"`
+	Error      string            `json:"error,omitempty"`
+	Validation string            `json:"validation,omitempty"`
+	Stream     *models.StreamURL `json:"stream,omitempty"`
+}
+
+func runExtract(_ *cobra.Command, args []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	config, err := storage.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	config.Set("cache", false)
+	system := extractors.NewSystem(config)
+
+	input := strings.TrimSpace(args[0])
+	report := extractReport{Input: input, EmbedURL: input}
+
+	if isAniWorldURL(input) {
+		client := aniworld.NewClient(system)
+		embedURL, err := client.FollowRedirect(ctx, input)
+		if err != nil {
+			return fmt.Errorf("failed to follow redirect: %w", err)
+		}
+		report.EmbedURL = embedURL
+		report.Redirected = embedURL != input
+	}
+
+	var attempts []extractors.Attempt
+	if extractAll {
+		attempts = system.ExtractAll(ctx, report.EmbedURL)
+	} else {
+		started := time.Now()
+		streamURL, err := system.Extract(ctx, report.EmbedURL)
+		name := ""
+		if streamURL != nil {
+			name = streamURL.Provider
+		}
+		attempts = []extractors.Attempt{{Extractor: name, Stream: streamURL, Err: err, Duration: time.Since(started)}}
+	}
+
+	for _, attempt := range attempts {
+		result := extractResult{
+			Extractor:  attempt.Extractor,
+			DurationMs: attempt.Duration.Milliseconds(),
+			Stream:     attempt.Stream,
+		}
+		if attempt.Err != nil {
+			result.Error = attempt.Err.Error()
+		}
+		if attempt.Stream != nil {
+			if report.Redirected {
+				attempt.Stream.Chain = append([]string{input}, attempt.Stream.Chain...)
+			}
+			if err := system.Validate(ctx, attempt.Stream); err != nil {
+				result.Validation = err.Error()
+			} else {
+				result.Validation = "ok"
+			}
+		}
+		report.Attempts = append(report.Attempts, result)
+	}
+
+	if extractJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+	} else {
+		printExtractReport(report)
+	}
+
+	for _, result := range report.Attempts {
+		if result.Stream != nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("no extractor produced a stream")
+}
+
+func printExtractReport(report extractReport) {
+	fmt.Printf("Input:     %s\n", report.Input)
+	if report.Redirected {
+		fmt.Printf("Embed:     %s\n", report.EmbedURL)
+	}
+
+	for _, result := range report.Attempts {
+		fmt.Println()
+
+		name := result.Extractor
+		if name == "" {
+			name = "(none)"
+		}
+		fmt.Printf("Extractor: %s (%dms)\n", name, result.DurationMs)
+
+		if result.Error != "" {
+			fmt.Printf("  Error:     %s\n", result.Error)
+		}
+
+		stream := result.Stream
+		if stream == nil {
+			continue
+		}
+
+		fmt.Printf("  Method:    %s\n", valueOr(stream.Method, "unknown"))
+		fmt.Printf("  Quality:   %s\n", stream.Quality.String())
+		if stream.Bandwidth > 0 {
+			fmt.Printf("  Bandwidth: %d\n", stream.Bandwidth)
+		}
+		fmt.Printf("  Expires:   %s (in %s)\n", stream.ExpiresAt.Format(time.RFC3339), time.Until(stream.ExpiresAt).Round(time.Second))
+		fmt.Printf("  Probe:     %s\n", result.Validation)
+
+		fmt.Println("  Chain:")
+		for i, link := range stream.Chain {
+			fmt.Printf("    %d. %s\n", i+1, link)
+		}
+		if len(stream.Chain) == 0 {
+			fmt.Printf("    1. %s\n", stream.URL)
+		}
+
+		if len(stream.Headers) > 0 {
+			fmt.Println("  Headers:")
+			keys := make([]string, 0, len(stream.Headers))
+			for key := range stream.Headers {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("    %s: %s\n", key, stream.Headers[key])
+			}
+		}
+
+		if cookie := stream.CookieHeader(); cookie != "" {
+			fmt.Printf("  Cookies:   %s\n", cookie)
+		}
+
+		if len(stream.Variants) > 0 {
+			fmt.Println("  Variants:")
+			for _, v := range stream.Variants {
+				fmt.Printf("    %-6s %4dx%-4d %9d bps  %s\n", v.Quality.String(), v.Width, v.Height, v.Bandwidth, v.URL)
+			}
+		}
+
+		if len(stream.Subtitles) > 0 {
+			fmt.Println("  Subtitles:")
+			for _, sub := range stream.Subtitles {
+				source := "external"
+				if sub.Embedded {
+					source = "playlist"
+				}
+				fmt.Printf("    [%s] %s (%s)  %s\n", valueOr(sub.Language, "?"), sub.Label, source, sub.URL)
+			}
+		}
+	}
+}
+
+func isAniWorldURL(input string) bool {
+	return strings.HasPrefix(input, "/redirect/") || strings.Contains(input, strings.TrimPrefix(aniworld.BaseURL, "https://"))
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hayasedb/hayase-cli/internal/extractors"
+	"github.com/hayasedb/hayase-cli/internal/storage"
+)
+
+var hostersReset bool
+
+var hostersCmd = &cobra.Command{
+	Use:   "hosters",
+	Short: "Show hoster health statistics",
+	Long: `Show success rate, latency and failure reasons recorded for each hoster.
+
+Hosters are ranked by a score that blends the extractor's static priority
+with its recent health.
+
+Examples:
+  hayase-cli hosters            # Show hoster stats
+  hayase-cli hosters --reset    # Forget all recorded stats`,
+
+	RunE: runHosters,
+}
+
+func init() {
+	rootCmd.AddCommand(hostersCmd)
+	hostersCmd.Flags().BoolVar(&hostersReset, "reset", false, "Reset recorded hoster statistics")
+}
+
+func runHosters(*cobra.Command, []string) error {
+	config, err := storage.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	system := extractors.NewSystem(config)
+	health := system.Health()
+	if health == nil {
+		return fmt.Errorf("hoster health store unavailable")
+	}
+
+	if hostersReset {
+		health.Reset()
+		if err := health.Save(); err != nil {
+			return fmt.Errorf("failed to reset hoster stats: %w", err)
+		}
+		fmt.Println("Hoster statistics reset")
+		return nil
+	}
+
+	names := make(map[string]bool)
+	for _, name := range health.Hosters() {
+		names[name] = true
+	}
+	priorities := make(map[string]int)
+	for _, extractor := range system.GetExtractors() {
+		names[extractor.Name()] = true
+		priorities[extractor.Name()] = extractor.Priority()
+	}
+
+	hosters := make([]string, 0, len(names))
+	for name := range names {
+		hosters = append(hosters, name)
+	}
+	sort.Slice(hosters, func(i, j int) bool {
+		si := system.Score(hosters[i], priorities[hosters[i]])
+		sj := system.Score(hosters[j], priorities[hosters[j]])
+		if si != sj {
+			return si > sj
+		}
+		return hosters[i] < hosters[j]
+	})
+
+	fmt.Printf("  %-14s %8s %6s %9s %10s  %-12s %s\n", "HOSTER", "PRIORITY", "SCORE", "ATTEMPTS", "SUCCESS", "LATENCY", "LAST FAILURE")
+
+	for _, name := range hosters {
+		h, _ := health.Get(name)
+
+		success := "-"
+		latency := "-"
+		if h.Attempts() > 0 {
+			success = fmt.Sprintf("%.0f%%", h.SuccessRate*100)
+		}
+		if h.LatencyMs > 0 {
+			latency = (time.Duration(h.LatencyMs) * time.Millisecond).Round(10 * time.Millisecond).String()
+		}
+
+		priority := "-"
+		if p, ok := priorities[name]; ok {
+			priority = fmt.Sprintf("%d", p)
+		}
+
+		fmt.Printf("  %-14s %8s %6.2f %9d %10s  %-12s %s\n",
+			name, priority, system.Score(name, priorities[name]), h.Attempts(), success, latency, formatFailure(h))
+	}
+
+	return nil
+}
+
+func formatFailure(h storage.HosterHealth) string {
+	if h.LastFailure.IsZero() {
+		return "-"
+	}
+
+	reasons := make([]string, 0, len(h.FailureReasons))
+	for reason, count := range h.FailureReasons {
+		reasons = append(reasons, fmt.Sprintf("%s×%d", reason, count))
+	}
+	sort.Strings(reasons)
+
+	return fmt.Sprintf("%s (%s ago) [%s]",
+		h.LastReason,
+		time.Since(h.LastFailure).Round(time.Minute).String(),
+		strings.Join(reasons, ", "))
+}
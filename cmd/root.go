@@ -6,6 +6,8 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
+	"strings"
 	"syscall"
 	"time"
 
@@ -13,10 +15,13 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 
+	"github.com/hayasedb/hayase-cli/internal/aniskip"
 	"github.com/hayasedb/hayase-cli/internal/extractors"
 	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/playback"
 	"github.com/hayasedb/hayase-cli/internal/players"
 	"github.com/hayasedb/hayase-cli/internal/players/mpv"
+	"github.com/hayasedb/hayase-cli/internal/players/vlc"
 	"github.com/hayasedb/hayase-cli/internal/providers"
 	"github.com/hayasedb/hayase-cli/internal/providers/aniworld"
 	"github.com/hayasedb/hayase-cli/internal/storage"
@@ -28,6 +33,11 @@ var (
 	seasonNum  int
 	episodeNum int
 	debug      bool
+	playerName string
+	startOver  bool
+	binge      bool
+	mpvProfile string
+	videoOut   string
 )
 
 var rootCmd = &cobra.Command{
@@ -43,7 +53,12 @@ Examples:
   hayase-cli                                    # Full TUI experience
   hayase-cli --anime "Attack on Titan"         # Skip search
   hayase-cli --anime "Naruto" --season 1       # Skip search and season
-  hayase-cli --anime "One Piece" -s 1 -e 1     # Direct play`,
+  hayase-cli --anime "One Piece" -s 1 -e 1     # Direct play
+  hayase-cli -a "One Piece" -s 1 -e 1 --start-over  # Ignore the saved position
+  hayase-cli -a "One Piece" -s 1 -e 1 --binge  # Keep playing the next episodes
+  hayase-cli --player vlc                       # Use VLC for this session
+  hayase-cli --profile laptop                   # Apply the "laptop" mpv option profile
+  hayase-cli --vo terminal                      # Render video in the terminal (e.g. over SSH)`,
 
 	RunE: runWatch,
 }
@@ -57,6 +72,11 @@ func init() {
 	rootCmd.Flags().IntVarP(&seasonNum, "season", "s", 0, "Season number")
 	rootCmd.Flags().IntVarP(&episodeNum, "episode", "e", 0, "Episode number")
 	rootCmd.Flags().BoolVar(&debug, "debug", false, "Enable debug logging")
+	rootCmd.Flags().BoolVar(&startOver, "start-over", false, "Ignore the saved position and play from the beginning")
+	rootCmd.Flags().BoolVar(&binge, "binge", false, "Play the following episodes automatically")
+	rootCmd.Flags().StringVar(&playerName, "player", "", "Player to use (mpv, vlc), overrides the configured player")
+	rootCmd.Flags().StringVar(&mpvProfile, "profile", "", "mpv option profile to apply, overrides the configured profile")
+	rootCmd.Flags().StringVar(&videoOut, "vo", "", "mpv video output (auto, window, terminal), overrides the configured output")
 }
 
 func runWatch(*cobra.Command, []string) error {
@@ -97,26 +117,58 @@ func runWatch(*cobra.Command, []string) error {
 		return fmt.Errorf("failed to load configuration: %w", err)
 	}
 
+	if binge {
+		config.Set("binge", true)
+	}
+
+	if videoOut != "" {
+		vo := strings.ToLower(videoOut)
+		if !slices.Contains([]string{mpv.VOAuto, mpv.VOWindow, mpv.VOTerminal}, vo) {
+			return fmt.Errorf("invalid video output %q (valid: auto, window, terminal)", videoOut)
+		}
+		config.Set("mpv.vo", vo)
+	}
+
+	if mpvProfile != "" {
+		profile := strings.ToLower(mpvProfile)
+		if !slices.Contains(config.GetMPVProfiles(), profile) {
+			return fmt.Errorf("unknown mpv profile %q (configured: %s)", mpvProfile, strings.Join(config.GetMPVProfiles(), ", "))
+		}
+		config.Set("mpv.profile", profile)
+	}
+
+	extractorSystem := extractors.NewSystem(config)
+
 	providerRegistry := providers.NewRegistry()
-	providerRegistry.Register("aniworld", aniworld.New())
+	providerRegistry.Register("aniworld", aniworld.New(extractorSystem))
 
 	provider, err := providerRegistry.GetDefault()
 	if err != nil {
 		return fmt.Errorf("no provider available: %w", err)
 	}
 
-	playerRegistry := players.NewRegistry()
-	playerRegistry.Register("mpv", mpv.New())
+	playerRegistry := newPlayerRegistry(config)
+	if playerName != "" {
+		if _, err := playerRegistry.Get(playerName); err != nil {
+			return err
+		}
+		playerRegistry.SetPreferred(playerName)
+	}
 
 	if _, err := playerRegistry.GetDefault(); err != nil {
 		return fmt.Errorf("no player available: %w", err)
 	}
 
+	progress, err := storage.NewProgressStore()
+	if err != nil {
+		log.Warn("Failed to load watch progress", "error", err)
+	}
+
 	if animeName != "" && seasonNum > 0 && episodeNum > 0 {
-		return playDirect(ctx, provider, playerRegistry, config, animeName, seasonNum, episodeNum)
+		return playDirect(ctx, provider, extractorSystem, playerRegistry, config, progress, animeName, seasonNum, episodeNum)
 	}
 
-	model := app.NewModel(ctx, cancel, provider, playerRegistry, config)
+	model := app.NewModel(ctx, cancel, provider, extractorSystem, playerRegistry, config, progress)
 
 	p := tea.NewProgram(
 		&model,
@@ -128,25 +180,15 @@ func runWatch(*cobra.Command, []string) error {
 	return err
 }
 
-func filterAvailableProviders(providers map[string]map[models.Language]string) map[string]map[models.Language]string {
-	extractorSystem := extractors.NewSystem(nil)
-	filtered := make(map[string]map[models.Language]string)
-
-	supportedNames := make(map[string]bool)
-	for _, extractor := range extractorSystem.GetExtractors() {
-		supportedNames[extractor.Name()] = true
-	}
-
-	for providerName, languages := range providers {
-		if supportedNames[providerName] {
-			filtered[providerName] = languages
-		}
-	}
-
-	return filtered
+func newPlayerRegistry(config *storage.Config) *players.Registry {
+	playerRegistry := players.NewRegistry()
+	playerRegistry.Register("mpv", mpv.New(config))
+	playerRegistry.Register("vlc", vlc.New(config))
+	playerRegistry.SetPreferred(config.GetPlayer())
+	return playerRegistry
 }
 
-func playDirect(ctx context.Context, provider providers.Provider, playerRegistry *players.Registry, config *storage.Config, animeName string, seasonNum, episodeNum int) error {
+func playDirect(ctx context.Context, provider providers.Provider, extractorSystem *extractors.System, playerRegistry *players.Registry, config *storage.Config, progress *storage.ProgressStore, animeName string, seasonNum, episodeNum int) error {
 	fmt.Printf("Searching for: %s\n", animeName)
 
 	results, err := provider.Search(ctx, animeName)
@@ -165,64 +207,177 @@ func playDirect(ctx context.Context, provider providers.Provider, playerRegistry
 		return fmt.Errorf("failed to get episodes: %w", err)
 	}
 
-	episode, err := provider.GetEpisode(ctx, anime, seasonNum, episodeNum)
+	player, err := playerRegistry.GetDefault()
 	if err != nil {
-		return fmt.Errorf("episode S%02dE%02d not found: %w", seasonNum, episodeNum, err)
+		return fmt.Errorf("no player available: %w", err)
 	}
 
-	fmt.Printf("Playing: %s\n", episode.String())
+	aniWorldProvider, ok := provider.(*aniworld.Provider)
+	if !ok {
+		return fmt.Errorf("provider is not AniWorld provider")
+	}
+	resolver := playback.Resolver{
+		Provider:   provider,
+		Extractors: extractorSystem,
+		Follow:     aniWorldProvider.GetClient().FollowRedirect,
+		Languages:  config.GetLanguagePriority(),
+	}
 
-	preferredLang := config.GetLanguage()
+	skipClient := aniskip.New(config.GetString("aniskip.url"))
+	tracks := config.GetTrackPreference(anime)
 
-	availableProviders := filterAvailableProviders(episode.Providers)
+	resume := !startOver
+	var prefetched *playback.Prefetch
 
-	var redirectURL string
-	var providerName string
-	var found bool
+	for {
+		episode, err := playback.LoadEpisode(ctx, provider, anime, seasonNum, episodeNum, prefetched)
+		if err != nil {
+			return fmt.Errorf("episode S%02dE%02d not found: %w", seasonNum, episodeNum, err)
+		}
 
-	for name, languages := range availableProviders {
-		if url, exists := languages[preferredLang]; exists {
-			redirectURL = url
-			providerName = name
-			found = true
-			break
+		var next *playback.Prefetch
+		if config.GetBinge() || config.GetPrefetch() {
+			if nextEpisode := anime.NextEpisode(episode.Season, episode.Episode); nextEpisode != nil {
+				next = playback.StartPrefetch(ctx, resolver, anime, nextEpisode)
+			}
+		}
+
+		var skips aniskip.Intervals
+		switch config.GetSkipMode() {
+		case "auto":
+			skips = playback.LoadSkipTimes(ctx, skipClient, anime, episode)
+		case "prompt":
+			intervals := playback.LoadSkipTimes(ctx, skipClient, anime, episode)
+			for _, interval := range intervals {
+				fmt.Printf("Skippable %s: %s - %s\n", interval.Label(), players.FormatPosition(interval.Start), players.FormatPosition(interval.End))
+			}
+			if len(intervals) > 0 {
+				fmt.Println("Skip prompts are only shown in the TUI, set aniskip.mode to auto to skip during direct play")
+			}
+		}
+
+		result, err := playEpisode(ctx, resolver, player, progress, anime, episode, prefetched, skips, tracks, resume)
+		if err != nil {
+			next.Cancel()
+			return err
 		}
-	}
 
-	if !found {
-		return fmt.Errorf("no stream available for this episode")
+		fmt.Println(result.String())
+
+		if !config.GetBinge() || next == nil || result.Reason != players.EndEOF {
+			next.Cancel()
+			return nil
+		}
+
+		if !waitForNextEpisode(ctx, next.Episode, config.GetBingeCountdown()) {
+			next.Cancel()
+			return nil
+		}
+
+		prefetched = next
+		seasonNum, episodeNum = next.Episode.Season, next.Episode.Episode
+		resume = true
 	}
+}
 
-	fmt.Printf("Extracting stream from %s...\n", providerName)
+func playEpisode(ctx context.Context, resolver playback.Resolver, player players.Player, progress *storage.ProgressStore, anime *models.Anime, episode *models.Episode, prefetched *playback.Prefetch, skips aniskip.Intervals, tracks models.TrackPreference, resume bool) (players.Result, error) {
+	fmt.Printf("Playing: %s\n", episode.String())
 
-	aniWorldProvider, ok := provider.(*aniworld.Provider)
-	if !ok {
-		return fmt.Errorf("provider is not AniWorld provider")
+	report := func(p extractors.Progress) {
+		switch p.Stage {
+		case extractors.StageTrying:
+			fmt.Printf("Extracting stream from %s...\n", p.Candidate.String())
+		case extractors.StageFailed:
+			fmt.Println(p.String())
+		}
 	}
 
-	streamURL, err := aniWorldProvider.GetClient().ExtractStreamURL(ctx, redirectURL)
-	if err != nil {
-		return fmt.Errorf("failed to extract stream URL: %w", err)
+	streamURL, candidate, rest, ok := prefetched.Stream(ctx)
+	prefetched.Cancel()
+
+	if ok {
+		fmt.Printf("Using prefetched stream from %s: %s quality\n", candidate.String(), streamURL.Quality.String())
+	} else {
+		candidates := resolver.Extractors.Rank(episode.Providers, resolver.Languages)
+		if len(candidates) == 0 {
+			return players.Result{}, fmt.Errorf("no stream available for this episode")
+		}
+
+		var index int
+		var err error
+		streamURL, index, err = resolver.Extractors.Resolve(ctx, candidates, resolver.Follow, report)
+		if err != nil {
+			return players.Result{}, fmt.Errorf("failed to extract stream URL: %w", err)
+		}
+		candidate, rest = candidates[index], candidates[index+1:]
+
+		fmt.Printf("Stream extracted: %s quality\n", streamURL.Quality.String())
 	}
 
-	fmt.Printf("Stream extracted: %s quality\n", streamURL.Quality.String())
+	source := playback.NewSource(resolver, candidate, rest, report)
+	opts := players.Options{
+		Title:  fmt.Sprintf("%s - %s", anime.Title, episode.String()),
+		Tracks: tracks,
+		Refresh: func(ctx context.Context) (*models.StreamURL, error) {
+			fmt.Println("Stream interrupted, extracting again...")
+			return source.Refresh(ctx)
+		},
+	}
+	if progress != nil && resume {
+		if position, ok := progress.Resume(anime, episode); ok {
+			opts.Start = position
+			fmt.Printf("Resuming from %s (use --start-over to play from the beginning)\n", players.FormatPosition(position))
+		}
+	}
 
-	player, err := playerRegistry.GetDefault()
-	if err != nil {
-		return fmt.Errorf("no player available: %w", err)
+	if len(skips) > 0 {
+		skipper := aniskip.NewSkipper(skips)
+		controls := make(chan players.Control, 1)
+		opts.Controls = controls
+		opts.Status = func(status players.Status) {
+			index, interval, ok := skipper.Pending(status.Position)
+			if !ok {
+				return
+			}
+			skipper.Handle(index)
+			fmt.Printf("Skipping %s\n", interval.Label())
+			select {
+			case controls <- players.SeekTo(interval.End):
+			default:
+			}
+		}
 	}
 
-	title := fmt.Sprintf("%s - %s", anime.Title, episode.String())
 	fmt.Printf("Starting playback with %s...\n", player.Name())
 
-	err = player.Play(ctx, streamURL, title)
+	result, err := player.Play(ctx, streamURL, opts)
+	log.Info("Playback ended",
+		"reason", result.Reason.String(),
+		"position", players.FormatPosition(result.Position),
+		"duration", players.FormatPosition(result.Duration),
+		"percent", fmt.Sprintf("%.0f", result.Percent()))
+	if progress != nil && (result.Position > 0 || result.Reason == players.EndEOF) {
+		progress.Record(anime, episode, result.Position, result.Duration, result.Reason == players.EndEOF)
+		if err := progress.Save(); err != nil {
+			log.Warn("Failed to save watch progress", "error", err)
+		}
+	}
 	if err != nil {
-		return fmt.Errorf("playback failed: %w", err)
+		return result, fmt.Errorf("playback failed: %w", err)
 	}
 
-	fmt.Println("Playback completed!")
+	return result, nil
+}
+
+func waitForNextEpisode(ctx context.Context, next *models.Episode, wait time.Duration) bool {
+	fmt.Printf("Up next: %s in %s (press Ctrl+C to stop)\n", next.String(), wait)
 
-	return nil
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(wait):
+		return true
+	}
 }
 
 func canAccessTTY() bool {
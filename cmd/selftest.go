@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/hayasedb/hayase-cli/internal/extractors"
+	"github.com/hayasedb/hayase-cli/internal/extractors/conformance"
+	"github.com/hayasedb/hayase-cli/internal/providers/aniworld"
+	"github.com/hayasedb/hayase-cli/internal/storage"
+)
+
+var (
+	selftestLive    bool
+	selftestAnime   string
+	selftestSeason  int
+	selftestEpisode int
+	selftestURLs    []string
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run built-in self checks",
+}
+
+var selftestExtractorsCmd = &cobra.Command{
+	Use:   "extractors",
+	Short: "Check extractors against recorded fixtures or live embeds",
+	Long: `Run every extractor against its recorded fixtures on a local test server.
+
+With --live the same checks run against real embeds instead: the hosters of
+a reference episode are resolved on aniworld (or the embeds given with --url)
+and each resulting stream is probed. Use this to find out whether a hoster
+changed its page format.
+
+Examples:
+  hayase-cli selftest extractors
+  hayase-cli selftest extractors --live
+  hayase-cli selftest extractors --live --anime "Frieren" -s 1 -e 1
+  hayase-cli selftest extractors --live --url https://voe.sx/e/abcdef`,
+
+	RunE: runSelftestExtractors,
+}
+
+func init() {
+	rootCmd.AddCommand(selftestCmd)
+	selftestCmd.AddCommand(selftestExtractorsCmd)
+
+	selftestExtractorsCmd.Flags().BoolVar(&selftestLive, "live", false, "Check against real embeds instead of recorded fixtures")
+	selftestExtractorsCmd.Flags().StringVar(&selftestAnime, "anime", "One Piece", "Reference anime for live checks")
+	selftestExtractorsCmd.Flags().IntVarP(&selftestSeason, "season", "s", 1, "Reference season for live checks")
+	selftestExtractorsCmd.Flags().IntVarP(&selftestEpisode, "episode", "e", 1, "Reference episode for live checks")
+	selftestExtractorsCmd.Flags().StringSliceVar(&selftestURLs, "url", nil, "Embed or redirect URL to check (repeatable, implies --live)")
+}
+
+func runSelftestExtractors(*cobra.Command, []string) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if selftestLive || len(selftestURLs) > 0 {
+		return runLiveSelftest(ctx)
+	}
+
+	fixtures, err := conformance.Fixtures()
+	if err != nil {
+		return fmt.Errorf("failed to load fixtures: %w", err)
+	}
+
+	system := extractors.NewSystem(nil)
+	failed := 0
+
+	for _, fixture := range fixtures {
+		extractor := conformance.FindExtractor(system.GetExtractors(), fixture.Extractor)
+		if extractor == nil {
+			fmt.Printf("FAIL  %-24s no extractor registered\n", fixture.String())
+			failed++
+			continue
+		}
+
+		started := time.Now()
+		if _, err := conformance.Run(ctx, extractor, fixture); err != nil {
+			fmt.Printf("FAIL  %-24s %v\n", fixture.String(), err)
+			failed++
+			continue
+		}
+		fmt.Printf("ok    %-24s %dms\n", fixture.String(), time.Since(started).Milliseconds())
+	}
+
+	return selftestSummary(len(fixtures), failed)
+}
+
+func runLiveSelftest(ctx context.Context) error {
+	config, err := storage.NewConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	config.Set("cache", false)
+	system := extractors.NewSystem(config)
+	client := aniworld.NewClient(system)
+
+	targets, err := liveSelftestTargets(ctx, system)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, target := range targets {
+		embedURL := target.url
+		if isAniWorldURL(embedURL) {
+			embedURL, err = client.FollowRedirect(ctx, embedURL)
+			if err != nil {
+				fmt.Printf("FAIL  %-12s redirect: %v\n", valueOr(target.name, "auto"), err)
+				failed++
+				continue
+			}
+		}
+
+		started := time.Now()
+		stream, err := system.ExtractWith(ctx, target.name, embedURL)
+		if err == nil {
+			err = conformance.CheckShape(stream)
+		}
+		if err == nil {
+			err = system.Validate(ctx, stream)
+		}
+		if err != nil {
+			fmt.Printf("FAIL  %-12s %s: %v\n", valueOr(target.name, "auto"), embedURL, err)
+			failed++
+			continue
+		}
+
+		fmt.Printf("ok    %-12s %s via %s, %s (%dms)\n", valueOr(target.name, stream.Provider), embedURL, stream.Method, stream.Quality.String(), time.Since(started).Milliseconds())
+	}
+
+	return selftestSummary(len(targets), failed)
+}
+
+type selftestTarget struct {
+	name string
+	url  string
+}
+
+func liveSelftestTargets(ctx context.Context, system *extractors.System) ([]selftestTarget, error) {
+	if len(selftestURLs) > 0 {
+		targets := make([]selftestTarget, 0, len(selftestURLs))
+		for _, url := range selftestURLs {
+			name := ""
+			for _, extractor := range system.GetExtractors() {
+				if extractor.CanHandle(url) {
+					name = extractor.Name()
+					break
+				}
+			}
+			if name == "" && !isAniWorldURL(url) {
+				return nil, fmt.Errorf("no extractor claims %s", url)
+			}
+			targets = append(targets, selftestTarget{name: name, url: url})
+		}
+		return targets, nil
+	}
+
+	provider := aniworld.New(system)
+
+	fmt.Printf("Resolving reference episode: %s S%02dE%02d\n", selftestAnime, selftestSeason, selftestEpisode)
+
+	results, err := provider.Search(ctx, selftestAnime)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no anime found for: %s", selftestAnime)
+	}
+
+	episode, err := provider.GetEpisode(ctx, results[0].Anime, selftestSeason, selftestEpisode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load reference episode: %w", err)
+	}
+
+	var targets []selftestTarget
+	for _, extractor := range system.GetExtractors() {
+		for hoster, links := range episode.Providers {
+			if !strings.EqualFold(hoster, extractor.Name()) {
+				continue
+			}
+
+			languages := make([]string, 0, len(links))
+			byLanguage := make(map[string]string, len(links))
+			for lang, link := range links {
+				languages = append(languages, lang.String())
+				byLanguage[lang.String()] = link
+			}
+			sort.Strings(languages)
+
+			if len(languages) > 0 {
+				targets = append(targets, selftestTarget{name: extractor.Name(), url: byLanguage[languages[0]]})
+			}
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("reference episode has no hosters with a registered extractor")
+	}
+
+	return targets, nil
+}
+
+func selftestSummary(total, failed int) error {
+	fmt.Printf("\n%d checks, %d failed\n", total, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d extractor checks failed", failed, total)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package aniskip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	DefaultBaseURL = "https://api.aniskip.com"
+
+	defaultTimeout = 5 * time.Second
+)
+
+var skipTypes = []string{"op", "ed", "mixed-op", "mixed-ed", "recap"}
+
+type Interval struct {
+	Type  string
+	Start time.Duration
+	End   time.Duration
+}
+
+func (i Interval) Contains(position time.Duration) bool {
+	return position >= i.Start && position < i.End
+}
+
+func (i Interval) Label() string {
+	switch i.Type {
+	case "op", "mixed-op":
+		return "opening"
+	case "ed", "mixed-ed":
+		return "ending"
+	case "recap":
+		return "recap"
+	default:
+		return i.Type
+	}
+}
+
+type Intervals []Interval
+
+func (s Intervals) At(position time.Duration) (int, bool) {
+	for i, interval := range s {
+		if interval.Contains(position) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func New(baseURL string) *Client {
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{Timeout: defaultTimeout},
+	}
+}
+
+type skipResponse struct {
+	Found   bool   `json:"found"`
+	Message string `json:"message"`
+	Results []struct {
+		Interval struct {
+			StartTime float64 `json:"startTime"`
+			EndTime   float64 `json:"endTime"`
+		} `json:"interval"`
+		SkipType      string  `json:"skipType"`
+		EpisodeLength float64 `json:"episodeLength"`
+	} `json:"results"`
+}
+
+func (c *Client) SkipTimes(ctx context.Context, malID, episode int, episodeLength time.Duration) (Intervals, error) {
+	if malID <= 0 || episode <= 0 {
+		return nil, fmt.Errorf("invalid MAL id %d or episode %d", malID, episode)
+	}
+
+	query := url.Values{"episodeLength": {fmt.Sprintf("%.0f", episodeLength.Seconds())}}
+	for _, skipType := range skipTypes {
+		query.Add("types[]", skipType)
+	}
+	target := fmt.Sprintf("%s/v2/skip-times/%d/%d?%s", c.baseURL, malID, episode, query.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Debug("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("skip times request returned status %d", resp.StatusCode)
+	}
+
+	var body skipResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode skip times: %w", err)
+	}
+
+	if !body.Found {
+		return nil, nil
+	}
+
+	var intervals Intervals
+	for _, result := range body.Results {
+		interval := Interval{
+			Type:  result.SkipType,
+			Start: seconds(result.Interval.StartTime),
+			End:   seconds(result.Interval.EndTime),
+		}
+		if interval.End <= interval.Start {
+			continue
+		}
+		intervals = append(intervals, interval)
+	}
+
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start < intervals[j].Start
+	})
+
+	log.Debug("Loaded skip times", "mal_id", malID, "episode", episode, "intervals", len(intervals))
+
+	return intervals, nil
+}
+
+func seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
+
+type Skipper struct {
+	intervals Intervals
+	handled   map[int]bool
+}
+
+func NewSkipper(intervals Intervals) *Skipper {
+	return &Skipper{
+		intervals: intervals,
+		handled:   make(map[int]bool),
+	}
+}
+
+func (s *Skipper) Pending(position time.Duration) (int, Interval, bool) {
+	if s == nil {
+		return -1, Interval{}, false
+	}
+
+	index, ok := s.intervals.At(position)
+	if !ok || s.handled[index] {
+		return -1, Interval{}, false
+	}
+	return index, s.intervals[index], true
+}
+
+func (s *Skipper) Handle(index int) {
+	if s != nil {
+		s.handled[index] = true
+	}
+}
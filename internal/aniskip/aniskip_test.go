@@ -0,0 +1,92 @@
+package aniskip_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hayasedb/hayase-cli/internal/aniskip"
+)
+
+const skipTimesResponse = `{
+  "found": true,
+  "results": [
+    {"interval": {"startTime": 1310.5, "endTime": 1400.0}, "skipType": "ed", "skipId": "b", "episodeLength": 1420.0},
+    {"interval": {"startTime": 52.25, "endTime": 142.25}, "skipType": "op", "skipId": "a", "episodeLength": 1420.0}
+  ],
+  "message": "Successfully found skip times",
+  "statusCode": 200
+}`
+
+func newServer(t *testing.T, handler http.HandlerFunc) *aniskip.Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return aniskip.New(server.URL + "/")
+}
+
+func TestSkipTimes(t *testing.T) {
+	client := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v2/skip-times/21/7" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		if types := r.URL.Query()["types[]"]; len(types) == 0 {
+			t.Error("request does not ask for any skip types")
+		}
+		if r.URL.Query().Get("episodeLength") != "0" {
+			t.Errorf("unexpected episodeLength %q", r.URL.Query().Get("episodeLength"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(skipTimesResponse))
+	})
+
+	intervals, err := client.SkipTimes(context.Background(), 21, 7, 0)
+	if err != nil {
+		t.Fatalf("SkipTimes failed: %v", err)
+	}
+	if len(intervals) != 2 {
+		t.Fatalf("expected 2 intervals, got %d", len(intervals))
+	}
+
+	opening := intervals[0]
+	if opening.Type != "op" || opening.Label() != "opening" {
+		t.Errorf("expected the opening first, got %+v", opening)
+	}
+	if opening.Start != 52250*time.Millisecond || opening.End != 142250*time.Millisecond {
+		t.Errorf("unexpected opening interval %s-%s", opening.Start, opening.End)
+	}
+
+	if index, ok := intervals.At(time.Minute); !ok || index != 0 {
+		t.Errorf("expected 1:00 to fall into the opening, got %d %v", index, ok)
+	}
+	if _, ok := intervals.At(10 * time.Minute); ok {
+		t.Error("expected 10:00 to fall outside every interval")
+	}
+}
+
+func TestSkipTimesNotFound(t *testing.T) {
+	client := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"found": false, "results": [], "message": "No episode found", "statusCode": 404}`))
+	})
+
+	intervals, err := client.SkipTimes(context.Background(), 21, 1100, 0)
+	if err != nil {
+		t.Fatalf("SkipTimes failed: %v", err)
+	}
+	if len(intervals) != 0 {
+		t.Errorf("expected no intervals, got %d", len(intervals))
+	}
+}
+
+func TestSkipTimesServerError(t *testing.T) {
+	client := newServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	if _, err := client.SkipTimes(context.Background(), 21, 1, 0); err == nil {
+		t.Error("expected an error for a failing server")
+	}
+}
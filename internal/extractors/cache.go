@@ -0,0 +1,89 @@
+package extractors
+
+import (
+	"context"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/extractors/hls"
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+const minCachedStreamLifetime = 5 * time.Minute
+
+func (s *System) ResolveURL(ctx context.Context, redirectURL string, follow RedirectFunc) (*models.StreamURL, error) {
+	return s.resolveCandidate(ctx, Candidate{RedirectURL: redirectURL}, follow)
+}
+
+func (s *System) followCached(ctx context.Context, redirectURL string, follow RedirectFunc) (string, error) {
+	if follow == nil {
+		return redirectURL, nil
+	}
+
+	if s.cache != nil {
+		if embedURL, ok := s.cache.GetEmbed(redirectURL); ok {
+			log.Debug("Using cached embed URL", "redirect_url", redirectURL, "embed_url", embedURL)
+			return embedURL, nil
+		}
+	}
+
+	embedURL, err := follow(ctx, redirectURL)
+	if err != nil {
+		return "", err
+	}
+
+	if s.cache != nil && embedURL != redirectURL {
+		s.cache.PutEmbed(redirectURL, embedURL)
+		s.saveCache()
+	}
+
+	return embedURL, nil
+}
+
+func (s *System) extractCached(ctx context.Context, hoster, embedURL string) (*models.StreamURL, bool, error) {
+	if s.cache != nil {
+		if streamURL, ok := s.cache.GetStream(embedURL, minCachedStreamLifetime); ok {
+			log.Debug("Using cached stream URL", "embed_url", embedURL, "expires_at", streamURL.ExpiresAt)
+			hls.ApplyPreference(streamURL, s.preferredQuality())
+			return streamURL, true, nil
+		}
+	}
+
+	streamURL, err := s.ExtractWith(ctx, hoster, embedURL)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if s.cache != nil {
+		s.cache.PutStream(embedURL, streamURL)
+		s.saveCache()
+	}
+
+	return streamURL, false, nil
+}
+
+func (s *System) invalidate(embedURL string) {
+	if s.cache == nil {
+		return
+	}
+	s.cache.Invalidate(embedURL)
+	s.saveCache()
+}
+
+func (s *System) saveCache() {
+	if err := s.cache.Save(); err != nil {
+		log.Debug("Failed to save stream cache", "error", err)
+	}
+}
+
+func (s *System) Forget(candidate Candidate) {
+	if s.cache == nil {
+		return
+	}
+	embedURL, ok := s.cache.GetEmbed(candidate.RedirectURL)
+	if !ok {
+		embedURL = candidate.RedirectURL
+	}
+	s.invalidate(embedURL)
+}
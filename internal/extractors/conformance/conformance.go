@@ -0,0 +1,246 @@
+package conformance
+
+import (
+	"context"
+	"crypto/tls"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+//go:embed fixtures
+var fixtureFS embed.FS
+
+type Route struct {
+	File        string `json:"file"`
+	ContentType string `json:"content_type,omitempty"`
+	Status      int    `json:"status,omitempty"`
+}
+
+type Expected struct {
+	Method        string            `json:"method"`
+	URL           string            `json:"url"`
+	Quality       string            `json:"quality"`
+	Variants      int               `json:"variants"`
+	MinTTLSeconds int               `json:"min_ttl_seconds"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Subtitles     []string          `json:"subtitles,omitempty"`
+}
+
+type Fixture struct {
+	Extractor string
+	Name      string
+	Entry     string           `json:"entry"`
+	Routes    map[string]Route `json:"routes"`
+	Expected  Expected         `json:"expected"`
+
+	dir string
+}
+
+func (f Fixture) String() string {
+	return f.Extractor + "/" + f.Name
+}
+
+type HTTPClientSetter interface {
+	SetHTTPClient(client *http.Client)
+}
+
+func Fixtures() ([]Fixture, error) {
+	manifests, err := fs.Glob(fixtureFS, "fixtures/*/*/manifest.json")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(manifests)
+
+	fixtures := make([]Fixture, 0, len(manifests))
+	for _, manifest := range manifests {
+		data, err := fixtureFS.ReadFile(manifest)
+		if err != nil {
+			return nil, err
+		}
+
+		var fixture Fixture
+		if err := json.Unmarshal(data, &fixture); err != nil {
+			return nil, fmt.Errorf("invalid fixture manifest %s: %w", manifest, err)
+		}
+
+		fixture.dir = path.Dir(manifest)
+		fixture.Name = path.Base(fixture.dir)
+		fixture.Extractor = path.Base(path.Dir(fixture.dir))
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures, nil
+}
+
+func FindExtractor(extractors []models.Extractor, name string) models.Extractor {
+	for _, extractor := range extractors {
+		if strings.EqualFold(extractor.Name(), name) {
+			return extractor
+		}
+	}
+	return nil
+}
+
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	requests []string
+}
+
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func NewServer(fixture Fixture) *Server {
+	server := &Server{}
+
+	server.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Host + r.URL.Path
+
+		server.mu.Lock()
+		server.requests = append(server.requests, key)
+		server.mu.Unlock()
+
+		route, exists := fixture.Routes[key]
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+
+		body, err := fixtureFS.ReadFile(path.Join(fixture.dir, route.File))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		contentType := route.ContentType
+		if contentType == "" {
+			contentType = "text/html; charset=utf-8"
+		}
+		w.Header().Set("Content-Type", contentType)
+
+		if route.Status != 0 {
+			w.WriteHeader(route.Status)
+		}
+		_, _ = w.Write(body)
+	}))
+
+	return server
+}
+
+func (s *Server) Client() *http.Client {
+	addr := s.Listener.Addr().String()
+	dialer := &net.Dialer{Timeout: 5 * time.Second}
+
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+}
+
+func Run(ctx context.Context, extractor models.Extractor, fixture Fixture) (*models.StreamURL, error) {
+	setter, ok := extractor.(HTTPClientSetter)
+	if !ok {
+		return nil, fmt.Errorf("extractor %s does not accept a custom HTTP client", extractor.Name())
+	}
+
+	server := NewServer(fixture)
+	defer server.Close()
+
+	setter.SetHTTPClient(server.Client())
+
+	stream, err := extractor.Extract(ctx, fixture.Entry)
+	if err != nil {
+		return nil, fmt.Errorf("extraction failed (requests: %s): %w", strings.Join(server.Requests(), ", "), err)
+	}
+
+	if err := Check(stream, fixture.Expected); err != nil {
+		return stream, err
+	}
+
+	return stream, nil
+}
+
+func Check(stream *models.StreamURL, expected Expected) error {
+	if err := CheckShape(stream); err != nil {
+		return err
+	}
+
+	var problems []string
+
+	if expected.Method != "" && stream.Method != expected.Method {
+		problems = append(problems, fmt.Sprintf("method = %q, want %q", stream.Method, expected.Method))
+	}
+	if expected.URL != "" && stream.URL != expected.URL {
+		problems = append(problems, fmt.Sprintf("url = %q, want %q", stream.URL, expected.URL))
+	}
+	if expected.Quality != "" && stream.Quality.String() != expected.Quality {
+		problems = append(problems, fmt.Sprintf("quality = %s, want %s", stream.Quality.String(), expected.Quality))
+	}
+	if len(stream.Variants) != expected.Variants {
+		problems = append(problems, fmt.Sprintf("variants = %d, want %d", len(stream.Variants), expected.Variants))
+	}
+	if expected.MinTTLSeconds > 0 {
+		if ttl := time.Until(stream.ExpiresAt); ttl < time.Duration(expected.MinTTLSeconds)*time.Second {
+			problems = append(problems, fmt.Sprintf("expires in %s, want at least %ds", ttl.Round(time.Second), expected.MinTTLSeconds))
+		}
+	}
+	for key, want := range expected.Headers {
+		if got := stream.Header(key); got != want {
+			problems = append(problems, fmt.Sprintf("header %s = %q, want %q", key, got, want))
+		}
+	}
+
+	var languages []string
+	for _, sub := range stream.Subtitles {
+		languages = append(languages, sub.Language)
+	}
+	if strings.Join(languages, ",") != strings.Join(expected.Subtitles, ",") {
+		problems = append(problems, fmt.Sprintf("subtitles = [%s], want [%s]", strings.Join(languages, ","), strings.Join(expected.Subtitles, ",")))
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("unexpected result: %s", strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+func CheckShape(stream *models.StreamURL) error {
+	if stream == nil {
+		return fmt.Errorf("no stream returned")
+	}
+	if !strings.HasPrefix(stream.URL, "http://") && !strings.HasPrefix(stream.URL, "https://") {
+		return fmt.Errorf("stream URL %q is not an HTTP URL", stream.URL)
+	}
+	if stream.Method == "" {
+		return fmt.Errorf("stream does not report the extraction method")
+	}
+	if stream.IsExpired() {
+		return fmt.Errorf("stream already expired at %s", stream.ExpiresAt.Format(time.RFC3339))
+	}
+	if stream.Provider == "" {
+		return fmt.Errorf("stream does not report its provider")
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package conformance_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hayasedb/hayase-cli/internal/extractors"
+	"github.com/hayasedb/hayase-cli/internal/extractors/conformance"
+)
+
+func TestExtractorFixtures(t *testing.T) {
+	fixtures, err := conformance.Fixtures()
+	if err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+	if len(fixtures) == 0 {
+		t.Fatal("no fixtures found")
+	}
+
+	for _, fixture := range fixtures {
+		t.Run(fixture.String(), func(t *testing.T) {
+			extractor := conformance.FindExtractor(extractors.NewSystem(nil).GetExtractors(), fixture.Extractor)
+			if extractor == nil {
+				t.Fatalf("no extractor registered for %q", fixture.Extractor)
+			}
+
+			if _, err := conformance.Run(context.Background(), extractor, fixture); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestEveryExtractorHasFixtures(t *testing.T) {
+	fixtures, err := conformance.Fixtures()
+	if err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+
+	covered := make(map[string]bool)
+	for _, fixture := range fixtures {
+		covered[strings.ToLower(fixture.Extractor)] = true
+	}
+
+	for _, extractor := range extractors.NewSystem(nil).GetExtractors() {
+		if !covered[strings.ToLower(extractor.Name())] {
+			t.Errorf("extractor %s has no conformance fixtures", extractor.Name())
+		}
+	}
+}
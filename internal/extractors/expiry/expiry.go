@@ -0,0 +1,217 @@
+package expiry
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTTL    = 2 * time.Hour
+	DefaultMargin = 2 * time.Minute
+
+	maxRelativeSeconds = 30 * 24 * 60 * 60
+	minUnixSeconds     = 1_000_000_000
+	minUnixMillis      = 1_000_000_000_000
+)
+
+var (
+	absoluteKeys = []string{"expires", "expire", "expiry", "exp", "expiration", "validto", "valid_to", "x-expires", "deadline"}
+	relativeKeys = []string{"e", "ttl", "lifetime"}
+	startKeys    = []string{"s", "st", "start", "starttime", "ts", "t"}
+	tokenKeys    = []string{"hdnts", "__token__", "hdntl", "token", "auth"}
+)
+
+func ExpiresAt(rawURL string, fallback time.Duration) time.Time {
+	now := time.Now()
+
+	expires, ok := Parse(rawURL, now)
+	if !ok {
+		if fallback <= 0 {
+			fallback = DefaultTTL
+		}
+		return now.Add(fallback)
+	}
+
+	return applyMargin(expires, now)
+}
+
+func Parse(rawURL string, now time.Time) (time.Time, bool) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	query := lowerQuery(parsed.Query())
+
+	if t, ok := fromAmazon(query); ok {
+		return t, true
+	}
+
+	for _, key := range absoluteKeys {
+		if t, ok := parseTimestamp(query[key]); ok {
+			return t, true
+		}
+	}
+
+	for _, key := range tokenKeys {
+		if t, ok := fromTokenString(query[key]); ok {
+			return t, true
+		}
+	}
+
+	for _, key := range relativeKeys {
+		seconds, err := strconv.ParseInt(query[key], 10, 64)
+		if err != nil || seconds <= 0 {
+			continue
+		}
+		if seconds >= minUnixSeconds {
+			if t, ok := parseTimestamp(query[key]); ok {
+				return t, true
+			}
+			continue
+		}
+		if seconds > maxRelativeSeconds {
+			continue
+		}
+		start := now
+		for _, startKey := range startKeys {
+			if t, ok := parseTimestamp(query[startKey]); ok {
+				start = t
+				break
+			}
+		}
+		return start.Add(time.Duration(seconds) * time.Second), true
+	}
+
+	for _, value := range query {
+		if t, ok := fromJWT(value); ok {
+			return t, true
+		}
+	}
+
+	for _, segment := range strings.Split(parsed.Path, "/") {
+		if t, ok := fromJWT(segment); ok {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func applyMargin(expires, now time.Time) time.Time {
+	remaining := expires.Sub(now)
+	if remaining <= 0 {
+		return expires
+	}
+
+	margin := DefaultMargin
+	if remaining < 2*margin {
+		margin = remaining / 2
+	}
+
+	return expires.Add(-margin)
+}
+
+func lowerQuery(values url.Values) map[string]string {
+	query := make(map[string]string, len(values))
+	for key, vals := range values {
+		if len(vals) > 0 {
+			query[strings.ToLower(key)] = vals[0]
+		}
+	}
+	return query
+}
+
+func parseTimestamp(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		switch {
+		case n >= minUnixMillis:
+			return time.UnixMilli(n), true
+		case n >= minUnixSeconds:
+			return time.Unix(n, 0), true
+		default:
+			return time.Time{}, false
+		}
+	}
+
+	for _, layout := range []string{time.RFC3339, "20060102T150405Z", time.RFC1123} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func fromAmazon(query map[string]string) (time.Time, bool) {
+	date, seconds := query["x-amz-date"], query["x-amz-expires"]
+	if date == "" || seconds == "" {
+		return time.Time{}, false
+	}
+
+	start, err := time.Parse("20060102T150405Z", date)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	n, err := strconv.ParseInt(seconds, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return start.Add(time.Duration(n) * time.Second), true
+}
+
+func fromTokenString(token string) (time.Time, bool) {
+	if token == "" {
+		return time.Time{}, false
+	}
+
+	if t, ok := fromJWT(token); ok {
+		return t, true
+	}
+
+	for _, field := range strings.FieldsFunc(token, func(r rune) bool { return r == '~' || r == '&' || r == ',' }) {
+		key, value, found := strings.Cut(field, "=")
+		if !found {
+			continue
+		}
+		if strings.EqualFold(key, "exp") || strings.EqualFold(key, "expires") {
+			if t, ok := parseTimestamp(value); ok {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func fromJWT(token string) (time.Time, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || len(parts[1]) < 8 {
+		return time.Time{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	var claims struct {
+		Exp json.Number `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == "" {
+		return time.Time{}, false
+	}
+
+	return parseTimestamp(claims.Exp.String())
+}
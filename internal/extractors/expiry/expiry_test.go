@@ -0,0 +1,92 @@
+package expiry
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+)
+
+func jwt(payload string) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	return encode([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + encode([]byte(payload)) + ".c2lnbmF0dXJl"
+}
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	unix := time.Unix(1_700_000_000, 0)
+
+	tests := []struct {
+		name string
+		url  string
+		want time.Time
+		ok   bool
+	}{
+		{
+			name: "amazon signature",
+			url:  "https://cdn.example/v.m3u8?X-Amz-Date=20240101T100000Z&X-Amz-Expires=3600",
+			want: time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+			ok:   true,
+		},
+		{name: "absolute seconds", url: "https://cdn.example/v.m3u8?expires=1700000000", want: unix, ok: true},
+		{name: "absolute milliseconds", url: "https://cdn.example/v.m3u8?Expires=1700000000000", want: unix, ok: true},
+		{
+			name: "absolute RFC3339",
+			url:  "https://cdn.example/v.m3u8?expiry=2024-01-01T13:00:00Z",
+			want: time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC),
+			ok:   true,
+		},
+		{name: "relative from now", url: "https://cdn.example/v.m3u8?ttl=600", want: now.Add(10 * time.Minute), ok: true},
+		{name: "relative from start key", url: "https://cdn.example/v.m3u8?s=1700000000&e=600", want: unix.Add(10 * time.Minute), ok: true},
+		{name: "relative key holding a timestamp", url: "https://cdn.example/v.m3u8?e=1700000000", want: unix, ok: true},
+		{name: "relative too long", url: "https://cdn.example/v.m3u8?ttl=99999999", ok: false},
+		{name: "akamai token", url: "https://cdn.example/v.m3u8?hdnts=st=1699990000~exp=1700000000~acl=/*~hmac=ab", want: unix, ok: true},
+		{name: "jwt token key", url: "https://cdn.example/v.m3u8?token=" + jwt(`{"exp":1700000000}`), want: unix, ok: true},
+		{name: "jwt in other query key", url: "https://cdn.example/v.m3u8?sig=" + jwt(`{"sub":"x","exp":1700000000}`), want: unix, ok: true},
+		{name: "jwt in path", url: "https://cdn.example/hls/" + jwt(`{"exp":1700000000}`) + "/index.m3u8", want: unix, ok: true},
+		{name: "jwt without exp", url: "https://cdn.example/v.m3u8?token=" + jwt(`{"sub":"x"}`), ok: false},
+		{name: "no expiry", url: "https://cdn.example/v.m3u8?quality=1080", ok: false},
+		{name: "small number is not a timestamp", url: "https://cdn.example/v.m3u8?expires=12345", ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Parse(tt.url, now)
+			if ok != tt.ok {
+				t.Fatalf("Parse ok = %v, want %v (got %v)", ok, tt.ok, got)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("Parse = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyMargin(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		expires time.Time
+		want    time.Time
+	}{
+		{name: "full margin", expires: now.Add(time.Hour), want: now.Add(time.Hour - DefaultMargin)},
+		{name: "halved for short lifetimes", expires: now.Add(3 * time.Minute), want: now.Add(90 * time.Second)},
+		{name: "already expired", expires: now.Add(-time.Minute), want: now.Add(-time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := applyMargin(tt.expires, now); !got.Equal(tt.want) {
+				t.Errorf("applyMargin = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpiresAtFallback(t *testing.T) {
+	before := time.Now()
+	got := ExpiresAt("https://cdn.example/v.m3u8", 0)
+	if got.Before(before.Add(DefaultTTL)) || got.After(time.Now().Add(DefaultTTL)) {
+		t.Errorf("ExpiresAt = %v, want about %v from now", got, DefaultTTL)
+	}
+}
@@ -3,10 +3,13 @@ package extractors
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sort"
+	"time"
 
 	"github.com/charmbracelet/log"
 
+	"github.com/hayasedb/hayase-cli/internal/extractors/hls"
 	"github.com/hayasedb/hayase-cli/internal/extractors/voe"
 	"github.com/hayasedb/hayase-cli/internal/models"
 	"github.com/hayasedb/hayase-cli/internal/storage"
@@ -15,15 +18,37 @@ import (
 type System struct {
 	extractors []models.Extractor
 	config     *storage.Config
+	health     *storage.HealthStore
+	cache      *storage.StreamCache
+	httpClient *http.Client
 }
 
 func NewSystem(config *storage.Config) *System {
 	system := &System{
 		config: config,
+		httpClient: &http.Client{
+			Timeout: probeTimeout,
+		},
 	}
 
 	system.registerExtractors()
 
+	if config != nil {
+		health, err := storage.NewHealthStore()
+		if err != nil {
+			log.Warn("Failed to load hoster health", "error", err)
+		}
+		system.health = health
+
+		if config.GetBool("cache") {
+			cache, err := storage.NewStreamCache()
+			if err != nil {
+				log.Warn("Failed to load stream cache", "error", err)
+			}
+			system.cache = cache
+		}
+	}
+
 	sort.Slice(system.extractors, func(i, j int) bool {
 		return system.extractors[i].Priority() > system.extractors[j].Priority()
 	})
@@ -55,22 +80,12 @@ func (s *System) Extract(ctx context.Context, embeddedURL string) (*models.Strea
 
 	var lastErr error
 	for _, extractor := range candidates {
-		log.Debug("Trying extractor", "extractor", extractor.Name())
-
-		streamURL, err := extractor.Extract(ctx, embeddedURL)
+		streamURL, err := s.extractWith(ctx, extractor, embeddedURL)
 		if err != nil {
-			log.Debug("Extractor failed", "extractor", extractor.Name(), "error", err)
 			lastErr = err
 			continue
 		}
-
-		if streamURL != nil && !streamURL.IsExpired() {
-			log.Info("Successfully extracted stream URL",
-				"extractor", extractor.Name(),
-				"provider", streamURL.Provider,
-				"quality", streamURL.Quality.String())
-			return streamURL, nil
-		}
+		return streamURL, nil
 	}
 
 	if lastErr != nil {
@@ -80,6 +95,73 @@ func (s *System) Extract(ctx context.Context, embeddedURL string) (*models.Strea
 	return nil, fmt.Errorf("no valid stream URL found")
 }
 
+type Attempt struct {
+	Extractor string
+	Stream    *models.StreamURL
+	Err       error
+	Duration  time.Duration
+}
+
+func (s *System) ExtractAll(ctx context.Context, embeddedURL string) []Attempt {
+	attempts := make([]Attempt, 0, len(s.extractors))
+	for _, extractor := range s.extractors {
+		started := time.Now()
+		streamURL, err := s.extractWith(ctx, extractor, embeddedURL)
+		attempts = append(attempts, Attempt{
+			Extractor: extractor.Name(),
+			Stream:    streamURL,
+			Err:       err,
+			Duration:  time.Since(started),
+		})
+	}
+	return attempts
+}
+
+func (s *System) ExtractWith(ctx context.Context, name, embeddedURL string) (*models.StreamURL, error) {
+	extractor := s.extractorFor(name)
+	if extractor == nil || extractor.CanHandle(embeddedURL) {
+		return s.Extract(ctx, embeddedURL)
+	}
+
+	log.Debug("Using hoster extractor for unrecognized embed domain", "extractor", extractor.Name(), "url", embeddedURL)
+
+	return s.extractWith(ctx, extractor, embeddedURL)
+}
+
+func (s *System) extractWith(ctx context.Context, extractor models.Extractor, embeddedURL string) (*models.StreamURL, error) {
+	log.Debug("Trying extractor", "extractor", extractor.Name())
+
+	streamURL, err := extractor.Extract(ctx, embeddedURL)
+	if err != nil {
+		log.Debug("Extractor failed", "extractor", extractor.Name(), "error", err)
+		return nil, err
+	}
+
+	if streamURL == nil {
+		return nil, fmt.Errorf("%s returned no stream URL", extractor.Name())
+	}
+
+	if streamURL.IsExpired() {
+		return nil, fmt.Errorf("%s returned an expired stream URL", extractor.Name())
+	}
+
+	hls.ApplyPreference(streamURL, s.preferredQuality())
+
+	log.Info("Successfully extracted stream URL",
+		"extractor", extractor.Name(),
+		"provider", streamURL.Provider,
+		"quality", streamURL.Quality.String())
+
+	return streamURL, nil
+}
+
+func (s *System) preferredQuality() models.Quality {
+	if s.config == nil {
+		return models.QualityBest
+	}
+	return s.config.GetQuality()
+}
+
 func (s *System) GetExtractors() []models.Extractor {
 	return s.extractors
 }
@@ -0,0 +1,101 @@
+package extractors
+
+import (
+	"context"
+	"errors"
+	"net"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/storage"
+)
+
+const (
+	minReliabilityWeight = 0.2
+	latencyReference     = 5 * time.Second
+	minSamplesForHealth  = 3
+)
+
+var statusPattern = regexp.MustCompile(`status (\d{3})`)
+
+func (s *System) Health() *storage.HealthStore {
+	return s.health
+}
+
+func (s *System) Score(hoster string, priority int) float64 {
+	base := float64(priority)
+	if s.health == nil {
+		return base
+	}
+
+	h, exists := s.health.Get(hoster)
+	if !exists || h.Attempts() < minSamplesForHealth {
+		return base
+	}
+
+	reliability := minReliabilityWeight + (1-minReliabilityWeight)*h.SuccessRate
+
+	latencyFactor := 1.0
+	if h.LatencyMs > 0 {
+		latencyFactor = 1 / (1 + h.LatencyMs/float64(latencyReference.Milliseconds()))
+	}
+
+	return base * reliability * (0.5 + 0.5*latencyFactor)
+}
+
+func (s *System) recordResult(hoster string, started time.Time, err error) {
+	if s.health == nil {
+		return
+	}
+
+	if err == nil {
+		s.health.RecordSuccess(hoster, time.Since(started))
+	} else {
+		reason := failureReason(err)
+		if reason == "" {
+			return
+		}
+		s.health.RecordFailure(hoster, reason)
+	}
+
+	if saveErr := s.health.Save(); saveErr != nil {
+		log.Debug("Failed to save hoster health", "error", saveErr)
+	}
+}
+
+func failureReason(err error) string {
+	if errors.Is(err, context.Canceled) {
+		return ""
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Kind.String()
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return "timeout"
+	}
+
+	message := err.Error()
+	if matches := statusPattern.FindStringSubmatch(message); len(matches) > 1 {
+		return "http_" + matches[1]
+	}
+
+	switch {
+	case strings.Contains(message, "failed to follow redirect"):
+		return "redirect"
+	case strings.Contains(message, "expired"):
+		return "expired"
+	case strings.Contains(message, "no redirect URL"), strings.Contains(message, "failed to extract"):
+		return "no_stream"
+	case strings.Contains(message, "no extractor"):
+		return "unsupported"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,363 @@
+package hls
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+const maxPlaylistSize = 1 << 20
+
+var defaultClient = &http.Client{
+	Timeout: 10 * time.Second,
+}
+
+type Rendition struct {
+	Type       string
+	GroupID    string
+	Language   string
+	Name       string
+	URI        string
+	Default    bool
+	AutoSelect bool
+	Forced     bool
+}
+
+type Playlist struct {
+	URL        string
+	Master     bool
+	Variants   []models.Variant
+	Renditions []Rendition
+	Segments   []string
+}
+
+func (p *Playlist) Group(mediaType, groupID string) []Rendition {
+	var renditions []Rendition
+	for _, r := range p.Renditions {
+		if r.Type == mediaType && r.GroupID == groupID {
+			renditions = append(renditions, r)
+		}
+	}
+	return renditions
+}
+
+func IsPlaylistURL(rawURL string) bool {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return strings.Contains(rawURL, ".m3u8")
+	}
+	return strings.HasSuffix(strings.ToLower(parsed.Path), ".m3u8")
+}
+
+func Fetch(ctx context.Context, client *http.Client, stream *models.StreamURL) (*Playlist, error) {
+	return FetchURL(ctx, client, stream.URL, stream)
+}
+
+func FetchURL(ctx context.Context, client *http.Client, playlistURL string, stream *models.StreamURL) (*Playlist, error) {
+	if client == nil {
+		client = defaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", playlistURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	if stream != nil {
+		ApplyHeaders(req, stream)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("playlist request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Debug("Failed to close response body", "error", err)
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("playlist returned status %d", resp.StatusCode)
+	}
+
+	finalURL := playlistURL
+	if resp.Request != nil && resp.Request.URL != nil {
+		finalURL = resp.Request.URL.String()
+	}
+
+	return Parse(io.LimitReader(resp.Body, maxPlaylistSize), finalURL)
+}
+
+func ApplyHeaders(req *http.Request, stream *models.StreamURL) {
+	for key, value := range stream.Headers {
+		req.Header.Set(key, value)
+	}
+	if cookie := stream.CookieHeader(); cookie != "" && req.Header.Get("Cookie") == "" {
+		req.Header.Set("Cookie", cookie)
+	}
+}
+
+func Parse(r io.Reader, baseURL string) (*Playlist, error) {
+	base, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid playlist URL: %w", err)
+	}
+
+	playlist := &Playlist{URL: baseURL}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxPlaylistSize)
+
+	first := true
+	var pending *models.Variant
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if first {
+			if !strings.HasPrefix(line, "#EXTM3U") {
+				return nil, fmt.Errorf("not an HLS playlist")
+			}
+			first = false
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "#EXT-X-STREAM-INF:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-STREAM-INF:"))
+			pending = &models.Variant{
+				Bandwidth:        atoi(attrs["BANDWIDTH"]),
+				AverageBandwidth: atoi(attrs["AVERAGE-BANDWIDTH"]),
+				Codecs:           attrs["CODECS"],
+				Audio:            attrs["AUDIO"],
+				Subtitles:        attrs["SUBTITLES"],
+			}
+			pending.Width, pending.Height = parseResolution(attrs["RESOLUTION"])
+			pending.Quality = models.Quality1080p
+			if pending.Height > 0 {
+				pending.Quality = models.QualityFromHeight(pending.Height)
+			}
+			if fps, err := strconv.ParseFloat(attrs["FRAME-RATE"], 64); err == nil {
+				pending.FrameRate = fps
+			}
+			playlist.Master = true
+
+		case strings.HasPrefix(line, "#EXT-X-MEDIA:"):
+			attrs := parseAttributes(strings.TrimPrefix(line, "#EXT-X-MEDIA:"))
+			rendition := Rendition{
+				Type:       attrs["TYPE"],
+				GroupID:    attrs["GROUP-ID"],
+				Language:   attrs["LANGUAGE"],
+				Name:       attrs["NAME"],
+				Default:    attrs["DEFAULT"] == "YES",
+				AutoSelect: attrs["AUTOSELECT"] == "YES",
+				Forced:     attrs["FORCED"] == "YES",
+			}
+			if uri := attrs["URI"]; uri != "" {
+				rendition.URI = resolve(base, uri)
+			}
+			playlist.Renditions = append(playlist.Renditions, rendition)
+			playlist.Master = true
+
+		case strings.HasPrefix(line, "#"):
+			continue
+
+		default:
+			if pending != nil {
+				pending.URL = resolve(base, line)
+				playlist.Variants = append(playlist.Variants, *pending)
+				pending = nil
+			} else {
+				playlist.Segments = append(playlist.Segments, resolve(base, line))
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read playlist: %w", err)
+	}
+
+	if first {
+		return nil, fmt.Errorf("empty playlist")
+	}
+
+	return playlist, nil
+}
+
+func Annotate(ctx context.Context, client *http.Client, stream *models.StreamURL) error {
+	if !IsPlaylistURL(stream.URL) {
+		return nil
+	}
+
+	playlist, err := Fetch(ctx, client, stream)
+	if err != nil {
+		return err
+	}
+
+	if !playlist.Master || len(playlist.Variants) == 0 {
+		return nil
+	}
+
+	stream.Variants = playlist.Variants
+
+	if best, ok := SelectVariant(stream.Variants, models.QualityBest); ok {
+		stream.Quality = best.Quality
+	}
+
+	for _, rendition := range playlist.Renditions {
+		if rendition.Type != "SUBTITLES" || rendition.URI == "" {
+			continue
+		}
+		stream.Subtitles = append(stream.Subtitles, models.Subtitle{
+			URL:      rendition.URI,
+			Language: rendition.Language,
+			Label:    rendition.Name,
+			Default:  rendition.Default,
+			Forced:   rendition.Forced,
+			Embedded: true,
+		})
+	}
+
+	log.Debug("Parsed HLS master playlist",
+		"variants", len(playlist.Variants),
+		"renditions", len(playlist.Renditions),
+		"subtitles", len(stream.Subtitles),
+		"best", stream.Quality.String())
+
+	return nil
+}
+
+func SelectVariant(variants []models.Variant, preferred models.Quality) (models.Variant, bool) {
+	if len(variants) == 0 {
+		return models.Variant{}, false
+	}
+
+	sorted := make([]models.Variant, len(variants))
+	copy(sorted, variants)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Height != sorted[j].Height {
+			return sorted[i].Height > sorted[j].Height
+		}
+		return sorted[i].Bandwidth > sorted[j].Bandwidth
+	})
+
+	switch preferred {
+	case models.QualityBest:
+		return sorted[0], true
+	case models.QualityWorst:
+		return sorted[len(sorted)-1], true
+	}
+
+	target := preferred.Height()
+	best := sorted[0]
+	bestDistance := -1
+	for _, v := range sorted {
+		height := v.Height
+		if height == 0 {
+			height = v.Quality.Height()
+		}
+		distance := height - target
+		if distance < 0 {
+			distance = -distance
+		}
+		if bestDistance < 0 || distance < bestDistance {
+			best = v
+			bestDistance = distance
+		}
+	}
+
+	return best, true
+}
+
+func ApplyPreference(stream *models.StreamURL, preferred models.Quality) {
+	variant, ok := SelectVariant(stream.Variants, preferred)
+	if !ok {
+		return
+	}
+
+	stream.Quality = variant.Quality
+	stream.Bandwidth = variant.Bandwidth
+
+	log.Debug("Selected HLS variant",
+		"preferred", preferred.String(),
+		"quality", variant.Quality.String(),
+		"bandwidth", variant.Bandwidth)
+}
+
+func parseAttributes(s string) map[string]string {
+	attrs := make(map[string]string)
+
+	for len(s) > 0 {
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 {
+			break
+		}
+		key := strings.TrimSpace(s[:eq])
+		s = s[eq+1:]
+
+		var value string
+		if strings.HasPrefix(s, `"`) {
+			end := strings.IndexByte(s[1:], '"')
+			if end < 0 {
+				value = s[1:]
+				s = ""
+			} else {
+				value = s[1 : end+1]
+				s = s[end+2:]
+			}
+		} else {
+			end := strings.IndexByte(s, ',')
+			if end < 0 {
+				value = s
+				s = ""
+			} else {
+				value = s[:end]
+				s = s[end:]
+			}
+		}
+
+		attrs[key] = value
+		s = strings.TrimPrefix(s, ",")
+	}
+
+	return attrs
+}
+
+func parseResolution(s string) (int, int) {
+	parts := strings.SplitN(strings.ToLower(s), "x", 2)
+	if len(parts) != 2 {
+		return 0, 0
+	}
+	return atoi(parts[0]), atoi(parts[1])
+}
+
+func atoi(s string) int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func resolve(base *url.URL, ref string) string {
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return base.ResolveReference(parsed).String()
+}
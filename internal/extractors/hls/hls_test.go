@@ -0,0 +1,184 @@
+package hls
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+func TestParseAttributes(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  map[string]string
+	}{
+		{
+			name:  "quoted commas in codecs",
+			input: `BANDWIDTH=2000000,CODECS="avc1.64001f,mp4a.40.2",RESOLUTION=1280x720`,
+			want:  map[string]string{"BANDWIDTH": "2000000", "CODECS": "avc1.64001f,mp4a.40.2", "RESOLUTION": "1280x720"},
+		},
+		{
+			name:  "quoted value last",
+			input: `TYPE=SUBTITLES,GROUP-ID="subs",NAME="English, SDH"`,
+			want:  map[string]string{"TYPE": "SUBTITLES", "GROUP-ID": "subs", "NAME": "English, SDH"},
+		},
+		{
+			name:  "unterminated quote",
+			input: `NAME="broken`,
+			want:  map[string]string{"NAME": "broken"},
+		},
+		{name: "empty", input: "", want: map[string]string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseAttributes(tt.input)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseAttributes = %v, want %v", got, tt.want)
+			}
+			for key, value := range tt.want {
+				if got[key] != value {
+					t.Errorf("%s = %q, want %q", key, got[key], value)
+				}
+			}
+		})
+	}
+}
+
+const masterPlaylist = `#EXTM3U
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",LANGUAGE="en",NAME="English",DEFAULT=YES,FORCED=NO,URI="subs/en.m3u8"
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="subs",LANGUAGE="de",NAME="Deutsch (Forced)",FORCED=YES,URI="/abs/de.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=800000,CODECS="avc1.4d401e,mp4a.40.2",RESOLUTION=640x360,SUBTITLES="subs"
+360/index.m3u8
+#EXT-X-STREAM-INF:BANDWIDTH=5000000,CODECS="avc1.640028,mp4a.40.2",FRAME-RATE=23.976,SUBTITLES="subs"
+../1080/index.m3u8?token=abc
+#EXT-X-STREAM-INF:BANDWIDTH=2500000,RESOLUTION=1280x720
+https://other.example/720.m3u8
+`
+
+func TestParseMaster(t *testing.T) {
+	playlist, err := Parse(strings.NewReader(masterPlaylist), "https://cdn.example/hls/master.m3u8")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if !playlist.Master {
+		t.Fatal("expected a master playlist")
+	}
+	if len(playlist.Variants) != 3 {
+		t.Fatalf("expected 3 variants, got %d", len(playlist.Variants))
+	}
+
+	tests := []struct {
+		uri    string
+		codecs string
+		height int
+	}{
+		{"https://cdn.example/hls/360/index.m3u8", "avc1.4d401e,mp4a.40.2", 360},
+		{"https://cdn.example/1080/index.m3u8?token=abc", "avc1.640028,mp4a.40.2", 0},
+		{"https://other.example/720.m3u8", "", 720},
+	}
+	for i, tt := range tests {
+		v := playlist.Variants[i]
+		if v.URL != tt.uri {
+			t.Errorf("variant %d URL = %q, want %q", i, v.URL, tt.uri)
+		}
+		if v.Codecs != tt.codecs {
+			t.Errorf("variant %d codecs = %q, want %q", i, v.Codecs, tt.codecs)
+		}
+		if v.Height != tt.height {
+			t.Errorf("variant %d height = %d, want %d", i, v.Height, tt.height)
+		}
+	}
+
+	if q := playlist.Variants[1].Quality; q != models.Quality1080p {
+		t.Errorf("variant without RESOLUTION quality = %s, want 1080p", q.String())
+	}
+	if playlist.Variants[1].FrameRate != 23.976 {
+		t.Errorf("frame rate = %v, want 23.976", playlist.Variants[1].FrameRate)
+	}
+
+	subs := playlist.Group("SUBTITLES", "subs")
+	if len(subs) != 2 {
+		t.Fatalf("expected 2 subtitle renditions, got %d", len(subs))
+	}
+	if subs[0].URI != "https://cdn.example/hls/subs/en.m3u8" || !subs[0].Default || subs[0].Forced {
+		t.Errorf("unexpected first rendition %+v", subs[0])
+	}
+	if subs[1].URI != "https://cdn.example/abs/de.m3u8" || !subs[1].Forced {
+		t.Errorf("unexpected second rendition %+v", subs[1])
+	}
+}
+
+func TestParseMedia(t *testing.T) {
+	media := "#EXTM3U\n#EXT-X-TARGETDURATION:10\n#EXTINF:10,\nseg0.ts\n#EXTINF:10,\n/seg/1.ts\n#EXT-X-ENDLIST\n"
+	playlist, err := Parse(strings.NewReader(media), "https://cdn.example/hls/720/index.m3u8")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if playlist.Master {
+		t.Error("media playlist parsed as master")
+	}
+	want := []string{"https://cdn.example/hls/720/seg0.ts", "https://cdn.example/seg/1.ts"}
+	if len(playlist.Segments) != len(want) {
+		t.Fatalf("segments = %v, want %v", playlist.Segments, want)
+	}
+	for i := range want {
+		if playlist.Segments[i] != want[i] {
+			t.Errorf("segment %d = %q, want %q", i, playlist.Segments[i], want[i])
+		}
+	}
+
+	if _, err := Parse(strings.NewReader("<html></html>"), "https://cdn.example/x.m3u8"); err == nil {
+		t.Error("expected an error for a non-playlist body")
+	}
+}
+
+func TestSelectVariant(t *testing.T) {
+	variants := []models.Variant{
+		{Height: 480, Bandwidth: 1_000_000, Quality: models.Quality480p},
+		{Height: 1080, Bandwidth: 5_000_000, Quality: models.Quality1080p},
+		{Height: 720, Bandwidth: 2_000_000, Quality: models.Quality720p},
+		{Height: 720, Bandwidth: 3_000_000, Quality: models.Quality720p},
+	}
+
+	tests := []struct {
+		name      string
+		preferred models.Quality
+		height    int
+		bandwidth int
+	}{
+		{"best", models.QualityBest, 1080, 5_000_000},
+		{"worst", models.QualityWorst, 480, 1_000_000},
+		{"exact prefers higher bandwidth", models.Quality720p, 720, 3_000_000},
+		{"nearest below", models.Quality360p, 480, 1_000_000},
+		{"nearest above", models.Quality2160p, 1080, 5_000_000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := SelectVariant(variants, tt.preferred)
+			if !ok {
+				t.Fatal("SelectVariant found nothing")
+			}
+			if got.Height != tt.height || got.Bandwidth != tt.bandwidth {
+				t.Errorf("SelectVariant = %dp@%d, want %dp@%d", got.Height, got.Bandwidth, tt.height, tt.bandwidth)
+			}
+		})
+	}
+
+	if _, ok := SelectVariant(nil, models.Quality720p); ok {
+		t.Error("SelectVariant on no variants should report false")
+	}
+}
+
+func TestSelectVariantWithoutHeight(t *testing.T) {
+	variants := []models.Variant{
+		{Bandwidth: 800_000, Quality: models.Quality360p},
+		{Bandwidth: 2_500_000, Quality: models.Quality720p},
+	}
+	got, _ := SelectVariant(variants, models.Quality720p)
+	if got.Bandwidth != 2_500_000 {
+		t.Errorf("SelectVariant fell back to %+v, want the 720p quality variant", got)
+	}
+}
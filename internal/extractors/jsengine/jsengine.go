@@ -0,0 +1,324 @@
+package jsengine
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/charmbracelet/log"
+	"github.com/dop251/goja"
+)
+
+const (
+	DefaultTimeout = 3 * time.Second
+
+	maxScriptSize    = 2 << 20
+	maxCallStackSize = 1024
+	maxTimers        = 64
+)
+
+var ErrTimeout = errors.New("script evaluation timed out")
+
+type Sandbox struct {
+	vm       *goja.Runtime
+	timeout  time.Duration
+	document *goquery.Document
+	timers   []goja.Callable
+	builtins map[string]bool
+}
+
+func NewSandbox(timeout time.Duration) *Sandbox {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	vm := goja.New()
+	vm.SetMaxCallStackSize(maxCallStackSize)
+
+	s := &Sandbox{
+		vm:      vm,
+		timeout: timeout,
+	}
+
+	s.installGlobals()
+
+	s.builtins = make(map[string]bool)
+	for _, key := range vm.GlobalObject().Keys() {
+		s.builtins[key] = true
+	}
+
+	return s
+}
+
+func (s *Sandbox) installGlobals() {
+	global := s.vm.GlobalObject()
+
+	_ = s.vm.Set("window", global)
+	_ = s.vm.Set("self", global)
+	_ = s.vm.Set("globalThis", global)
+
+	_ = s.vm.Set("atob", func(encoded string) (string, error) {
+		decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil {
+			return "", fmt.Errorf("atob: %w", err)
+		}
+		return string(decoded), nil
+	})
+
+	_ = s.vm.Set("btoa", func(raw string) string {
+		return base64.StdEncoding.EncodeToString([]byte(raw))
+	})
+
+	console := s.vm.NewObject()
+	logFn := func(call goja.FunctionCall) goja.Value {
+		args := make([]interface{}, 0, len(call.Arguments))
+		for _, arg := range call.Arguments {
+			args = append(args, arg.String())
+		}
+		log.Debug("Sandbox console", "args", args)
+		return goja.Undefined()
+	}
+	_ = console.Set("log", logFn)
+	_ = console.Set("warn", logFn)
+	_ = console.Set("error", logFn)
+	_ = console.Set("debug", logFn)
+	_ = s.vm.Set("console", console)
+
+	schedule := func(call goja.FunctionCall) goja.Value {
+		if fn, ok := goja.AssertFunction(call.Argument(0)); ok && len(s.timers) < maxTimers {
+			s.timers = append(s.timers, fn)
+		}
+		return s.vm.ToValue(len(s.timers))
+	}
+	_ = s.vm.Set("setTimeout", schedule)
+	_ = s.vm.Set("setInterval", schedule)
+	_ = s.vm.Set("clearTimeout", func(goja.FunctionCall) goja.Value { return goja.Undefined() })
+	_ = s.vm.Set("clearInterval", func(goja.FunctionCall) goja.Value { return goja.Undefined() })
+
+	location := s.vm.NewObject()
+	_ = location.Set("href", "")
+	_ = location.Set("hostname", "")
+	_ = s.vm.Set("location", location)
+
+	navigator := s.vm.NewObject()
+	_ = navigator.Set("userAgent", "Mozilla/5.0 (X11; Linux x86_64; rv:98.0) Gecko/20100101 Firefox/98.0")
+	_ = s.vm.Set("navigator", navigator)
+
+	_ = s.vm.Set("document", s.newDocument())
+}
+
+func (s *Sandbox) SetDocument(html string) error {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return fmt.Errorf("failed to parse document: %w", err)
+	}
+	s.document = doc
+	return nil
+}
+
+func (s *Sandbox) SetLocation(pageURL string) {
+	location := s.vm.Get("location").ToObject(s.vm)
+	_ = location.Set("href", pageURL)
+	if host := hostOf(pageURL); host != "" {
+		_ = location.Set("hostname", host)
+	}
+}
+
+func (s *Sandbox) Set(name string, value interface{}) error {
+	return s.vm.Set(name, value)
+}
+
+func (s *Sandbox) Run(ctx context.Context, name, src string) error {
+	if len(src) > maxScriptSize {
+		return fmt.Errorf("script %s exceeds %d bytes", name, maxScriptSize)
+	}
+
+	_, err := s.guard(ctx, func() (goja.Value, error) {
+		if _, err := s.vm.RunScript(name, src); err != nil {
+			return nil, err
+		}
+		return nil, s.flushTimers()
+	})
+	return err
+}
+
+func (s *Sandbox) Eval(ctx context.Context, expr string) (interface{}, error) {
+	value, err := s.guard(ctx, func() (goja.Value, error) {
+		return s.vm.RunString(expr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if value == nil || goja.IsUndefined(value) || goja.IsNull(value) {
+		return nil, nil
+	}
+	return value.Export(), nil
+}
+
+func (s *Sandbox) Globals() map[string]interface{} {
+	globals := make(map[string]interface{})
+	for _, key := range s.vm.GlobalObject().Keys() {
+		if s.builtins[key] {
+			continue
+		}
+		value := s.vm.Get(key)
+		if value == nil || goja.IsUndefined(value) || goja.IsNull(value) {
+			continue
+		}
+		if _, isFunc := goja.AssertFunction(value); isFunc {
+			continue
+		}
+		globals[key] = value.Export()
+	}
+	return globals
+}
+
+func (s *Sandbox) guard(ctx context.Context, fn func() (goja.Value, error)) (goja.Value, error) {
+	var mu sync.Mutex
+	finished := false
+	interrupt := func(reason error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !finished {
+			s.vm.Interrupt(reason)
+		}
+	}
+
+	timer := time.AfterFunc(s.timeout, func() {
+		interrupt(ErrTimeout)
+	})
+	stop := context.AfterFunc(ctx, func() {
+		interrupt(ctx.Err())
+	})
+	defer func() {
+		timer.Stop()
+		stop()
+
+		mu.Lock()
+		finished = true
+		mu.Unlock()
+
+		s.vm.ClearInterrupt()
+	}()
+
+	value, err := fn()
+	if err != nil {
+		var interrupted *goja.InterruptedError
+		if errors.As(err, &interrupted) {
+			if cause, ok := interrupted.Value().(error); ok {
+				return nil, cause
+			}
+		}
+		return nil, fmt.Errorf("script error: %w", err)
+	}
+
+	return value, nil
+}
+
+func (s *Sandbox) flushTimers() error {
+	for i := 0; i < len(s.timers); i++ {
+		if _, err := s.timers[i](goja.Undefined()); err != nil {
+			return err
+		}
+	}
+	s.timers = nil
+	return nil
+}
+
+func (s *Sandbox) newDocument() *goja.Object {
+	document := s.vm.NewObject()
+
+	_ = document.Set("querySelector", func(selector string) goja.Value {
+		if s.document == nil {
+			return goja.Null()
+		}
+		sel := s.document.Find(selector).First()
+		if sel.Length() == 0 {
+			return goja.Null()
+		}
+		return s.newElement(sel)
+	})
+
+	_ = document.Set("querySelectorAll", func(selector string) goja.Value {
+		return s.newElementList(selector)
+	})
+
+	_ = document.Set("getElementById", func(id string) goja.Value {
+		if s.document == nil {
+			return goja.Null()
+		}
+		sel := s.document.Find("[id='" + id + "']").First()
+		if sel.Length() == 0 {
+			return goja.Null()
+		}
+		return s.newElement(sel)
+	})
+
+	_ = document.Set("getElementsByTagName", func(tag string) goja.Value {
+		return s.newElementList(tag)
+	})
+
+	_ = document.Set("createElement", func(tag string) goja.Value {
+		element := s.vm.NewObject()
+		_ = element.Set("tagName", strings.ToUpper(tag))
+		_ = element.Set("style", s.vm.NewObject())
+		_ = element.Set("setAttribute", func(goja.FunctionCall) goja.Value { return goja.Undefined() })
+		_ = element.Set("appendChild", func(goja.FunctionCall) goja.Value { return goja.Undefined() })
+		return element
+	})
+
+	_ = document.Set("addEventListener", func(call goja.FunctionCall) goja.Value {
+		if fn, ok := goja.AssertFunction(call.Argument(1)); ok && len(s.timers) < maxTimers {
+			s.timers = append(s.timers, fn)
+		}
+		return goja.Undefined()
+	})
+
+	_ = document.Set("readyState", "complete")
+
+	return document
+}
+
+func (s *Sandbox) newElementList(selector string) goja.Value {
+	var elements []interface{}
+	if s.document != nil {
+		s.document.Find(selector).Each(func(i int, sel *goquery.Selection) {
+			elements = append(elements, s.newElement(sel))
+		})
+	}
+	return s.vm.NewArray(elements...)
+}
+
+func (s *Sandbox) newElement(sel *goquery.Selection) *goja.Object {
+	element := s.vm.NewObject()
+
+	html, _ := sel.Html()
+	_ = element.Set("textContent", sel.Text())
+	_ = element.Set("innerText", sel.Text())
+	_ = element.Set("innerHTML", html)
+	_ = element.Set("tagName", strings.ToUpper(goquery.NodeName(sel)))
+	_ = element.Set("id", sel.AttrOr("id", ""))
+	_ = element.Set("getAttribute", func(name string) goja.Value {
+		if value, exists := sel.Attr(name); exists {
+			return s.vm.ToValue(value)
+		}
+		return goja.Null()
+	})
+	_ = element.Set("style", s.vm.NewObject())
+
+	return element
+}
+
+func hostOf(pageURL string) string {
+	parsed, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
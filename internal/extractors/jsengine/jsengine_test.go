@@ -0,0 +1,102 @@
+package jsengine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hayasedb/hayase-cli/internal/extractors/jsengine"
+)
+
+func TestRunawayScriptTimesOut(t *testing.T) {
+	sandbox := jsengine.NewSandbox(50 * time.Millisecond)
+
+	tests := []struct {
+		name string
+		run  func() error
+	}{
+		{"run", func() error { return sandbox.Run(context.Background(), "loop.js", "while(true){}") }},
+		{"eval", func() error {
+			_, err := sandbox.Eval(context.Background(), "for(;;){}")
+			return err
+		}},
+		{"timer", func() error {
+			return sandbox.Run(context.Background(), "timer.js", "setTimeout(function(){ while(true){} }, 0)")
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan error, 1)
+			go func() { done <- tt.run() }()
+
+			select {
+			case err := <-done:
+				if !errors.Is(err, jsengine.ErrTimeout) {
+					t.Errorf("error = %v, want %v", err, jsengine.ErrTimeout)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("runaway script was not interrupted")
+			}
+		})
+	}
+}
+
+func TestContextCancellationInterrupts(t *testing.T) {
+	sandbox := jsengine.NewSandbox(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sandbox.Run(ctx, "loop.js", "while(true){}") }()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("cancelled script was not interrupted")
+	}
+
+	if _, err := sandbox.Eval(context.Background(), "1 + 1"); err != nil {
+		t.Errorf("sandbox unusable after cancellation: %v", err)
+	}
+}
+
+func TestInterruptClearedAfterEvaluation(t *testing.T) {
+	timeout := 20 * time.Millisecond
+	sandbox := jsengine.NewSandbox(timeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := sandbox.Run(ctx, "setup.js", "var answer = 41"); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	cancel()
+	time.Sleep(3 * timeout)
+
+	value, err := sandbox.Eval(context.Background(), "answer + 1")
+	if err != nil {
+		t.Fatalf("stale interrupt hit a later evaluation: %v", err)
+	}
+	if value != int64(42) {
+		t.Errorf("Eval = %v, want 42", value)
+	}
+}
+
+func TestTimersRunAfterScript(t *testing.T) {
+	sandbox := jsengine.NewSandbox(0)
+	src := `var order = []; setTimeout(function(){ order.push("timer") }, 100); order.push("script")`
+	if err := sandbox.Run(context.Background(), "timers.js", src); err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	value, err := sandbox.Eval(context.Background(), `order.join(",")`)
+	if err != nil {
+		t.Fatalf("Eval failed: %v", err)
+	}
+	if value != "script,timer" {
+		t.Errorf("order = %v, want script,timer", value)
+	}
+}
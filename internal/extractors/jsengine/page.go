@@ -0,0 +1,88 @@
+package jsengine
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	streamURLPattern = regexp.MustCompile(`^https?://\S+\.(m3u8|mp4|mpd)(\?\S*)?$`)
+	sourceKeys       = []string{"source", "hls", "file", "src", "url"}
+)
+
+type PageScripts struct {
+	Inline   []string
+	External []string
+}
+
+func Scripts(html, pageURL string) (*PageScripts, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		return nil, err
+	}
+
+	base, _ := url.Parse(pageURL)
+	scripts := &PageScripts{}
+
+	doc.Find("script").Each(func(i int, s *goquery.Selection) {
+		scriptType := strings.ToLower(s.AttrOr("type", ""))
+		if scriptType != "" && !strings.Contains(scriptType, "javascript") && scriptType != "module" {
+			return
+		}
+
+		if src, exists := s.Attr("src"); exists {
+			if base != nil {
+				if resolved, err := base.Parse(src); err == nil {
+					src = resolved.String()
+				}
+			}
+			scripts.External = append(scripts.External, src)
+			return
+		}
+
+		if text := strings.TrimSpace(s.Text()); text != "" {
+			scripts.Inline = append(scripts.Inline, text)
+		}
+	})
+
+	return scripts, nil
+}
+
+func FindStreamURL(value interface{}) string {
+	return findStreamURL(value, 0)
+}
+
+func findStreamURL(value interface{}, depth int) string {
+	if depth > 6 {
+		return ""
+	}
+
+	switch v := value.(type) {
+	case string:
+		if streamURLPattern.MatchString(v) {
+			return v
+		}
+	case map[string]interface{}:
+		for _, key := range sourceKeys {
+			if s, ok := v[key].(string); ok && strings.HasPrefix(s, "http") {
+				return s
+			}
+		}
+		for _, nested := range v {
+			if found := findStreamURL(nested, depth+1); found != "" {
+				return found
+			}
+		}
+	case []interface{}:
+		for _, nested := range v {
+			if found := findStreamURL(nested, depth+1); found != "" {
+				return found
+			}
+		}
+	}
+
+	return ""
+}
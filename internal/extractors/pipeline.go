@@ -0,0 +1,360 @@
+package extractors
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+const defaultRaceGrace = 300 * time.Millisecond
+
+type Candidate struct {
+	Hoster      string
+	Language    models.Language
+	RedirectURL string
+	Priority    int
+	Score       float64
+}
+
+func (c Candidate) String() string {
+	return fmt.Sprintf("%s (%s)", c.Hoster, c.Language.String())
+}
+
+type RedirectFunc func(ctx context.Context, redirectURL string) (string, error)
+
+type ProgressStage int
+
+const (
+	StageTrying ProgressStage = iota
+	StageFailed
+	StageResolved
+)
+
+type Progress struct {
+	Stage     ProgressStage
+	Candidate Candidate
+	Next      *Candidate
+	Err       error
+}
+
+func (p Progress) String() string {
+	switch p.Stage {
+	case StageTrying:
+		return fmt.Sprintf("Trying %s", p.Candidate.String())
+	case StageFailed:
+		if p.Next == nil {
+			return fmt.Sprintf("%s failed", p.Candidate.Hoster)
+		}
+		if p.Next.Hoster == p.Candidate.Hoster {
+			return fmt.Sprintf("%s failed, trying %s", p.Candidate.String(), p.Next.String())
+		}
+		return fmt.Sprintf("%s failed, trying %s", p.Candidate.Hoster, p.Next.Hoster)
+	case StageResolved:
+		return fmt.Sprintf("Resolved stream from %s", p.Candidate.String())
+	default:
+		return ""
+	}
+}
+
+type ProgressFunc func(Progress)
+
+func (s *System) Rank(providers map[string]map[models.Language]string, languages []models.Language) []Candidate {
+	languageRank := make(map[models.Language]int)
+	for i, lang := range languages {
+		if _, exists := languageRank[lang]; !exists {
+			languageRank[lang] = i
+		}
+	}
+
+	var candidates []Candidate
+	for hoster, links := range providers {
+		extractor := s.extractorFor(hoster)
+		if extractor == nil {
+			continue
+		}
+
+		for lang, redirectURL := range links {
+			if redirectURL == "" {
+				continue
+			}
+			candidates = append(candidates, Candidate{
+				Hoster:      hoster,
+				Language:    lang,
+				RedirectURL: redirectURL,
+				Priority:    extractor.Priority(),
+				Score:       s.Score(hoster, extractor.Priority()),
+			})
+		}
+	}
+
+	rankOf := func(lang models.Language) int {
+		if rank, ok := languageRank[lang]; ok {
+			return rank
+		}
+		return len(languageRank) + int(lang)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if rankOf(a.Language) != rankOf(b.Language) {
+			return rankOf(a.Language) < rankOf(b.Language)
+		}
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.Hoster < b.Hoster
+	})
+
+	return candidates
+}
+
+func (s *System) Resolve(ctx context.Context, candidates []Candidate, follow RedirectFunc, progress ProgressFunc) (*models.StreamURL, int, error) {
+	if len(candidates) == 0 {
+		return nil, -1, fmt.Errorf("no supported hosters available")
+	}
+
+	report := func(p Progress) {
+		if progress != nil {
+			progress(p)
+		}
+	}
+
+	if race := s.raceSize(); race > 1 {
+		return s.resolveRace(ctx, candidates, follow, report, race)
+	}
+
+	var lastErr error
+	for i, candidate := range candidates {
+		if err := ctx.Err(); err != nil {
+			return nil, -1, err
+		}
+
+		report(Progress{Stage: StageTrying, Candidate: candidate})
+
+		streamURL, err := s.resolveAndRecord(ctx, candidate, follow)
+		if err != nil {
+			log.Warn("Hoster failed", "hoster", candidate.Hoster, "language", candidate.Language.String(), "error", err)
+			lastErr = err
+
+			var next *Candidate
+			if i+1 < len(candidates) {
+				next = &candidates[i+1]
+			}
+			report(Progress{Stage: StageFailed, Candidate: candidate, Next: next, Err: err})
+			continue
+		}
+
+		report(Progress{Stage: StageResolved, Candidate: candidate})
+		return streamURL, i, nil
+	}
+
+	return nil, -1, fmt.Errorf("all %d hosters failed, last error: %w", len(candidates), lastErr)
+}
+
+type raceResult struct {
+	index     int
+	streamURL *models.StreamURL
+	err       error
+}
+
+func (s *System) resolveRace(ctx context.Context, candidates []Candidate, follow RedirectFunc, report ProgressFunc, size int) (*models.StreamURL, int, error) {
+	grace := s.raceGrace()
+
+	var lastErr error
+	for start := 0; start < len(candidates); start += size {
+		if err := ctx.Err(); err != nil {
+			return nil, -1, err
+		}
+
+		end := min(start+size, len(candidates))
+		batch := candidates[start:end]
+
+		log.Debug("Racing hoster candidates", "from", start, "count", len(batch), "grace", grace)
+
+		streamURL, index, err := s.raceBatch(ctx, batch, follow, report, grace)
+		if err == nil {
+			report(Progress{Stage: StageResolved, Candidate: batch[index]})
+			return streamURL, start + index, nil
+		}
+		lastErr = err
+
+		if end < len(candidates) {
+			report(Progress{Stage: StageFailed, Candidate: batch[len(batch)-1], Next: &candidates[end], Err: err})
+		}
+	}
+
+	return nil, -1, fmt.Errorf("all %d hosters failed, last error: %w", len(candidates), lastErr)
+}
+
+func (s *System) raceBatch(ctx context.Context, batch []Candidate, follow RedirectFunc, report ProgressFunc, grace time.Duration) (*models.StreamURL, int, error) {
+	raceCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan raceResult, len(batch))
+	for i, candidate := range batch {
+		report(Progress{Stage: StageTrying, Candidate: candidate})
+
+		go func(index int, candidate Candidate) {
+			streamURL, err := s.resolveAndRecord(raceCtx, candidate, follow)
+			results <- raceResult{index: index, streamURL: streamURL, err: err}
+		}(i, candidate)
+	}
+
+	finished := make([]bool, len(batch))
+	streams := make([]*models.StreamURL, len(batch))
+	var lastErr error
+	var graceTimer <-chan time.Time
+
+	pick := func() int {
+		for i := range batch {
+			if streams[i] != nil {
+				return i
+			}
+			if !finished[i] {
+				return -1
+			}
+		}
+		return -1
+	}
+
+	bestSoFar := func() int {
+		for i := range batch {
+			if streams[i] != nil {
+				return i
+			}
+		}
+		return -1
+	}
+
+	for pending := len(batch); pending > 0; {
+		select {
+		case <-ctx.Done():
+			return nil, -1, ctx.Err()
+
+		case <-graceTimer:
+			if best := bestSoFar(); best >= 0 {
+				log.Debug("Race grace window elapsed", "winner", batch[best].Hoster)
+				return streams[best], best, nil
+			}
+
+		case result := <-results:
+			pending--
+			finished[result.index] = true
+			candidate := batch[result.index]
+
+			if result.err != nil {
+				log.Warn("Hoster failed", "hoster", candidate.Hoster, "language", candidate.Language.String(), "error", result.err)
+				lastErr = result.err
+				if raceCtx.Err() == nil {
+					report(Progress{Stage: StageFailed, Candidate: candidate, Err: result.err})
+				}
+			} else {
+				streams[result.index] = result.streamURL
+				if graceTimer == nil {
+					graceTimer = time.After(grace)
+				}
+			}
+
+			if winner := pick(); winner >= 0 {
+				return streams[winner], winner, nil
+			}
+		}
+	}
+
+	if best := bestSoFar(); best >= 0 {
+		return streams[best], best, nil
+	}
+
+	return nil, -1, lastErr
+}
+
+func (s *System) raceSize() int {
+	if s.config == nil || !s.config.GetBool("race") {
+		return 0
+	}
+	return s.config.GetInt("race_candidates")
+}
+
+func (s *System) raceGrace() time.Duration {
+	if s.config == nil {
+		return defaultRaceGrace
+	}
+	if ms := s.config.GetInt("race_grace_ms"); ms > 0 {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return defaultRaceGrace
+}
+
+func (s *System) resolveCandidate(ctx context.Context, candidate Candidate, follow RedirectFunc) (*models.StreamURL, error) {
+	embedURL, err := s.followCached(ctx, candidate.RedirectURL, follow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to follow redirect: %w", err)
+	}
+
+	log.Debug("Resolved embed URL", "hoster", candidate.Hoster, "redirect_url", candidate.RedirectURL, "embed_url", embedURL)
+
+	streamURL, cached, err := s.extractCached(ctx, candidate.Hoster, embedURL)
+	if err != nil {
+		return nil, err
+	}
+	streamURL.Chain = withRedirect(streamURL.Chain, candidate.RedirectURL, embedURL)
+
+	if !s.validationEnabled() {
+		return streamURL, nil
+	}
+
+	err = s.Validate(ctx, streamURL)
+	if err != nil && cached {
+		log.Debug("Cached stream failed validation, extracting again", "embed_url", embedURL, "error", err)
+		s.invalidate(embedURL)
+
+		streamURL, _, err = s.extractCached(ctx, candidate.Hoster, embedURL)
+		if err != nil {
+			return nil, err
+		}
+		streamURL.Chain = withRedirect(streamURL.Chain, candidate.RedirectURL, embedURL)
+		err = s.Validate(ctx, streamURL)
+	}
+	if err != nil {
+		s.invalidate(embedURL)
+		return nil, err
+	}
+
+	return streamURL, nil
+}
+
+func withRedirect(chain []string, redirectURL, embedURL string) []string {
+	if redirectURL == "" || redirectURL == embedURL {
+		return chain
+	}
+	if len(chain) == 0 || chain[0] != embedURL {
+		chain = append([]string{embedURL}, chain...)
+	}
+	return append([]string{redirectURL}, chain...)
+}
+
+func (s *System) validationEnabled() bool {
+	return s.config == nil || s.config.GetBool("validate")
+}
+
+func (s *System) resolveAndRecord(ctx context.Context, candidate Candidate, follow RedirectFunc) (*models.StreamURL, error) {
+	started := time.Now()
+	streamURL, err := s.resolveCandidate(ctx, candidate, follow)
+	s.recordResult(candidate.Hoster, started, err)
+	return streamURL, err
+}
+
+func (s *System) extractorFor(hoster string) models.Extractor {
+	for _, extractor := range s.extractors {
+		if strings.EqualFold(extractor.Name(), hoster) {
+			return extractor
+		}
+	}
+	return nil
+}
@@ -0,0 +1,226 @@
+package extractors
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/extractors/hls"
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+const (
+	probeTimeout   = 8 * time.Second
+	probeRangeSize = 4096
+)
+
+type ValidationKind int
+
+const (
+	ValidationForbidden ValidationKind = iota
+	ValidationNotFound
+	ValidationGeoBlocked
+	ValidationExpired
+	ValidationBadContent
+	ValidationUnreachable
+)
+
+func (k ValidationKind) String() string {
+	switch k {
+	case ValidationForbidden:
+		return "forbidden"
+	case ValidationNotFound:
+		return "not_found"
+	case ValidationGeoBlocked:
+		return "geo_blocked"
+	case ValidationExpired:
+		return "expired"
+	case ValidationBadContent:
+		return "bad_content"
+	case ValidationUnreachable:
+		return "unreachable"
+	default:
+		return "unknown"
+	}
+}
+
+type ValidationError struct {
+	Kind   ValidationKind
+	Status int
+	URL    string
+	Err    error
+}
+
+func (e *ValidationError) Error() string {
+	switch {
+	case e.Err != nil:
+		return fmt.Sprintf("stream validation failed (%s): %v", e.Kind, e.Err)
+	case e.Status != 0:
+		return fmt.Sprintf("stream validation failed (%s): status %d", e.Kind, e.Status)
+	default:
+		return fmt.Sprintf("stream validation failed (%s)", e.Kind)
+	}
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+var geoBlockMarkers = []string{
+	"not available in your country",
+	"not available in your region",
+	"not available in your location",
+	"geo-restricted",
+	"geo restricted",
+	"geoblocked",
+	"geo-blocked",
+	"blocked in your country",
+	"blocked in your region",
+}
+
+func (s *System) Validate(ctx context.Context, stream *models.StreamURL) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	if stream.IsExpired() {
+		return &ValidationError{Kind: ValidationExpired, URL: stream.URL}
+	}
+
+	if !hls.IsPlaylistURL(stream.URL) {
+		return s.probeMedia(ctx, stream, stream.URL)
+	}
+
+	body, err := s.probe(ctx, stream, stream.URL, false)
+	if err != nil {
+		return err
+	}
+
+	playlist, err := hls.Parse(bytes.NewReader(body), stream.URL)
+	if err != nil {
+		return &ValidationError{Kind: ValidationBadContent, URL: stream.URL, Err: err}
+	}
+
+	if playlist.Master {
+		variantURL := pickProbeVariant(playlist, stream.Bandwidth)
+		if variantURL == "" {
+			return &ValidationError{Kind: ValidationBadContent, URL: stream.URL, Err: errors.New("master playlist has no variants")}
+		}
+
+		body, err = s.probe(ctx, stream, variantURL, false)
+		if err != nil {
+			return err
+		}
+
+		playlist, err = hls.Parse(bytes.NewReader(body), variantURL)
+		if err != nil {
+			return &ValidationError{Kind: ValidationBadContent, URL: variantURL, Err: err}
+		}
+	}
+
+	if len(playlist.Segments) == 0 {
+		return &ValidationError{Kind: ValidationBadContent, URL: playlist.URL, Err: errors.New("media playlist has no segments")}
+	}
+
+	return s.probeMedia(ctx, stream, playlist.Segments[0])
+}
+
+func (s *System) probeMedia(ctx context.Context, stream *models.StreamURL, mediaURL string) error {
+	_, err := s.probe(ctx, stream, mediaURL, true)
+	return err
+}
+
+func (s *System) probe(ctx context.Context, stream *models.StreamURL, target string, ranged bool) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, &ValidationError{Kind: ValidationBadContent, URL: target, Err: err}
+	}
+
+	hls.ApplyHeaders(req, stream)
+	if ranged {
+		req.Header.Set("Range", fmt.Sprintf("bytes=0-%d", probeRangeSize-1))
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil && errors.Is(ctx.Err(), context.Canceled) {
+			return nil, ctx.Err()
+		}
+		return nil, &ValidationError{Kind: ValidationUnreachable, URL: target, Err: err}
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Debug("Failed to close response body", "error", err)
+		}
+	}()
+
+	limit := int64(probeRangeSize)
+	if !ranged {
+		limit = 1 << 20
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return nil, &ValidationError{Kind: ValidationUnreachable, URL: target, Status: resp.StatusCode, Err: err}
+	}
+
+	if err := classifyStatus(resp.StatusCode, body, target); err != nil {
+		return nil, err
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if contentType == "text/html" {
+		return nil, &ValidationError{Kind: ValidationBadContent, URL: target, Status: resp.StatusCode, Err: errors.New("received an HTML page instead of media")}
+	}
+
+	if ranged && len(body) == 0 {
+		return nil, &ValidationError{Kind: ValidationBadContent, URL: target, Status: resp.StatusCode, Err: errors.New("empty response")}
+	}
+
+	log.Debug("Stream probe succeeded", "url", target, "status", resp.StatusCode, "content_type", contentType)
+
+	return body, nil
+}
+
+func classifyStatus(status int, body []byte, target string) error {
+	switch {
+	case status == http.StatusOK || status == http.StatusPartialContent:
+		return nil
+	case status == http.StatusUnavailableForLegalReasons:
+		return &ValidationError{Kind: ValidationGeoBlocked, Status: status, URL: target}
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		text := strings.ToLower(string(body))
+		for _, marker := range geoBlockMarkers {
+			if strings.Contains(text, marker) {
+				return &ValidationError{Kind: ValidationGeoBlocked, Status: status, URL: target}
+			}
+		}
+		return &ValidationError{Kind: ValidationForbidden, Status: status, URL: target}
+	case status == http.StatusGone:
+		return &ValidationError{Kind: ValidationExpired, Status: status, URL: target}
+	case status == http.StatusNotFound:
+		return &ValidationError{Kind: ValidationNotFound, Status: status, URL: target}
+	default:
+		return &ValidationError{Kind: ValidationUnreachable, Status: status, URL: target}
+	}
+}
+
+func pickProbeVariant(playlist *hls.Playlist, bandwidth int) string {
+	if len(playlist.Variants) == 0 {
+		return ""
+	}
+
+	for _, v := range playlist.Variants {
+		if bandwidth > 0 && v.Bandwidth == bandwidth {
+			return v.URL
+		}
+	}
+
+	return playlist.Variants[0].URL
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strings"
 	"time"
@@ -14,6 +15,9 @@ import (
 	"github.com/PuerkitoBio/goquery"
 	"github.com/charmbracelet/log"
 
+	"github.com/hayasedb/hayase-cli/internal/extractors/expiry"
+	"github.com/hayasedb/hayase-cli/internal/extractors/hls"
+	"github.com/hayasedb/hayase-cli/internal/extractors/jsengine"
 	"github.com/hayasedb/hayase-cli/internal/models"
 )
 
@@ -26,8 +30,17 @@ type Extractor struct {
 	b64Pattern      *regexp.Regexp
 	hlsPattern      *regexp.Regexp
 	junkParts       []string
+
+	scriptTimeout time.Duration
 }
 
+const (
+	userAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:98.0) Gecko/20100101 Firefox/98.0"
+
+	maxSandboxScripts    = 8
+	maxSandboxScriptSize = 1 << 20
+)
+
 func New() models.Extractor {
 	return &Extractor{
 		name:     "VOE",
@@ -46,9 +59,15 @@ func New() models.Extractor {
 		hlsPattern:      regexp.MustCompile(`'hls': '([^']+)'`),
 
 		junkParts: []string{"@$", "^^", "~@", "%?", "*~", "!!", "#&"},
+
+		scriptTimeout: jsengine.DefaultTimeout,
 	}
 }
 
+func (e *Extractor) SetHTTPClient(client *http.Client) {
+	e.httpClient = client
+}
+
 func (e *Extractor) Name() string {
 	return e.name
 }
@@ -135,17 +154,22 @@ func (e *Extractor) Extract(ctx context.Context, embeddedURL string) (*models.St
 
 	if streamURL := e.extractFromScript(html); streamURL != "" {
 		log.Debug("Successfully extracted using method 1", "method", "script_tag")
-		return e.createStreamURL(streamURL), nil
+		return e.finishStream(ctx, streamURL, embeddedURL, redirectURL, html, "script_tag"), nil
 	}
 
 	if streamURL := e.extractFromB64Variable(html); streamURL != "" {
 		log.Debug("Successfully extracted using method 2", "method", "b64_variable")
-		return e.createStreamURL(streamURL), nil
+		return e.finishStream(ctx, streamURL, embeddedURL, redirectURL, html, "b64_variable"), nil
 	}
 
 	if streamURL := e.extractHLSSource(html); streamURL != "" {
 		log.Debug("Successfully extracted using method 3", "method", "hls_pattern")
-		return e.createStreamURL(streamURL), nil
+		return e.finishStream(ctx, streamURL, embeddedURL, redirectURL, html, "hls_pattern"), nil
+	}
+
+	if streamURL := e.extractWithSandbox(ctx, html, redirectURL); streamURL != "" {
+		log.Debug("Successfully extracted using method 4", "method", "js_sandbox")
+		return e.finishStream(ctx, streamURL, embeddedURL, redirectURL, html, "js_sandbox"), nil
 	}
 
 	log.Debug("All extraction methods failed")
@@ -226,6 +250,78 @@ func (e *Extractor) extractHLSSource(html string) string {
 	return result
 }
 
+func (e *Extractor) extractWithSandbox(ctx context.Context, html, pageURL string) string {
+	scripts, err := jsengine.Scripts(html, pageURL)
+	if err != nil {
+		log.Debug("Failed to collect page scripts", "error", err)
+		return ""
+	}
+
+	sandbox := jsengine.NewSandbox(e.scriptTimeout)
+	if err := sandbox.SetDocument(html); err != nil {
+		log.Debug("Failed to load document into sandbox", "error", err)
+		return ""
+	}
+	sandbox.SetLocation(pageURL)
+
+	sources := scripts.Inline[:min(len(scripts.Inline), maxSandboxScripts)]
+	for _, scriptURL := range scripts.External {
+		if len(sources) >= maxSandboxScripts {
+			break
+		}
+		if !sameHost(scriptURL, pageURL) {
+			continue
+		}
+		if src, err := e.fetchScript(ctx, scriptURL); err == nil {
+			sources = append(sources, src)
+		} else {
+			log.Debug("Failed to fetch hoster script", "url", scriptURL, "error", err)
+		}
+	}
+
+	for i, src := range sources {
+		if err := sandbox.Run(ctx, fmt.Sprintf("voe-%d.js", i), src); err != nil {
+			log.Debug("Hoster script failed in sandbox", "index", i, "error", err)
+			if ctx.Err() != nil {
+				return ""
+			}
+		}
+	}
+
+	return jsengine.FindStreamURL(sandbox.Globals())
+}
+
+func (e *Extractor) fetchScript(ctx context.Context, scriptURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", scriptURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "Mozilla/5.0")
+
+	resp, err := e.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			return
+		}
+	}(resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("script returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxSandboxScriptSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read script: %w", err)
+	}
+
+	return string(body), nil
+}
+
 func (e *Extractor) decodeVOEString(encoded string) (map[string]interface{}, error) {
 	step1 := e.shiftLetters(encoded)
 
@@ -284,27 +380,98 @@ func (e *Extractor) shiftBack(input string, n int) string {
 	return string(result)
 }
 
-func (e *Extractor) createStreamURL(url string) *models.StreamURL {
-	quality := models.Quality1080p
+func (e *Extractor) finishStream(ctx context.Context, source, embeddedURL, pageURL, html, method string) *models.StreamURL {
+	stream := e.createStreamURL(source, pageURL)
+	stream.Method = method
+	stream.Chain = []string{embeddedURL, pageURL, source}
+	stream.Subtitles = e.extractSubtitles(html, pageURL)
+
+	if err := hls.Annotate(ctx, e.httpClient, stream); err != nil {
+		log.Debug("Failed to inspect HLS playlist", "url", source, "error", err)
+	}
+
+	return stream
+}
 
-	if strings.Contains(url, "720") {
-		quality = models.Quality720p
-	} else if strings.Contains(url, "1080") {
-		quality = models.Quality1080p
-	} else if strings.Contains(url, "1440") {
-		quality = models.Quality1440p
-	} else if strings.Contains(url, "2160") {
-		quality = models.Quality2160p
+func (e *Extractor) extractSubtitles(html, pageURL string) []models.Subtitle {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+	if err != nil {
+		log.Debug("Failed to parse HTML for subtitle tracks", "error", err)
+		return nil
 	}
 
-	expiresAt := time.Now().Add(2 * time.Hour)
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
 
-	return &models.StreamURL{
-		URL:       url,
+	var subtitles []models.Subtitle
+	doc.Find("track").Each(func(i int, s *goquery.Selection) {
+		kind := strings.ToLower(s.AttrOr("kind", "subtitles"))
+		if kind != "subtitles" && kind != "captions" {
+			return
+		}
+
+		src, err := url.Parse(strings.TrimSpace(s.AttrOr("src", "")))
+		if err != nil || src.String() == "" {
+			return
+		}
+
+		_, isDefault := s.Attr("default")
+		subtitles = append(subtitles, models.Subtitle{
+			URL:      base.ResolveReference(src).String(),
+			Language: s.AttrOr("srclang", ""),
+			Label:    s.AttrOr("label", ""),
+			Default:  isDefault,
+		})
+	})
+
+	if len(subtitles) > 0 {
+		log.Debug("Found subtitle tracks", "count", len(subtitles))
+	}
+
+	return subtitles
+}
+
+func (e *Extractor) createStreamURL(source, pageURL string) *models.StreamURL {
+	quality := models.Quality1080p
+
+	expiresAt := expiry.ExpiresAt(source, expiry.DefaultTTL)
+
+	stream := &models.StreamURL{
+		URL:       source,
 		Quality:   quality,
 		Provider:  e.Name(),
 		ExpiresAt: expiresAt,
 	}
+
+	stream.SetHeader("User-Agent", userAgent)
+	if origin := originOf(pageURL); origin != "" {
+		stream.SetHeader("Referer", origin+"/")
+		stream.SetHeader("Origin", origin)
+	}
+
+	return stream
+}
+
+func originOf(pageURL string) string {
+	parsed, err := url.Parse(pageURL)
+	if err != nil || parsed.Scheme == "" || parsed.Host == "" {
+		return ""
+	}
+	return parsed.Scheme + "://" + parsed.Host
+}
+
+func sameHost(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return ua.Hostname() == ub.Hostname()
 }
 
 func reverseString(s string) string {
@@ -11,6 +11,7 @@ type Anime struct {
 	Link        string    `json:"link"`
 	Description string    `json:"description"`
 	Year        int       `json:"year"`
+	MALID       int       `json:"mal_id,omitempty"`
 	Episodes    []Episode `json:"episodes"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
@@ -35,6 +36,40 @@ func (a *Anime) GetAvailableSeasons() []int {
 	return seasons
 }
 
+func (a *Anime) NextEpisode(season, episode int) *Episode {
+	var next *Episode
+	for i := range a.Episodes {
+		ep := &a.Episodes[i]
+		if ep.Season < season || (ep.Season == season && ep.Episode <= episode) {
+			continue
+		}
+		if season == 0 && ep.Season != 0 {
+			continue
+		}
+		if next == nil || ep.Season < next.Season || (ep.Season == next.Season && ep.Episode < next.Episode) {
+			next = ep
+		}
+	}
+	return next
+}
+
+func (a *Anime) PreviousEpisode(season, episode int) *Episode {
+	var previous *Episode
+	for i := range a.Episodes {
+		ep := &a.Episodes[i]
+		if ep.Season > season || (ep.Season == season && ep.Episode >= episode) {
+			continue
+		}
+		if (season == 0) != (ep.Season == 0) {
+			continue
+		}
+		if previous == nil || ep.Season > previous.Season || (ep.Season == previous.Season && ep.Episode > previous.Episode) {
+			previous = ep
+		}
+	}
+	return previous
+}
+
 func (a *Anime) GetEpisodesForSeason(season int) []Episode {
 	var episodes []Episode
 	for _, ep := range a.Episodes {
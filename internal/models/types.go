@@ -3,6 +3,9 @@ package models
 import (
 	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 )
@@ -28,6 +31,10 @@ func (l Language) String() string {
 	}
 }
 
+func AllLanguages() []Language {
+	return []Language{GerSub, EngSub, GerDub}
+}
+
 func ParseLanguage(s string) (Language, error) {
 	switch strings.ToLower(strings.TrimSpace(s)) {
 	case "ger-sub", "german-sub", "deutsch-sub":
@@ -48,10 +55,18 @@ const (
 	Quality1080p
 	Quality1440p
 	Quality2160p
+	Quality360p
+	Quality480p
+	QualityBest
+	QualityWorst
 )
 
 func (q Quality) String() string {
 	switch q {
+	case Quality360p:
+		return "360p"
+	case Quality480p:
+		return "480p"
 	case Quality720p:
 		return "720p"
 	case Quality1080p:
@@ -60,22 +75,281 @@ func (q Quality) String() string {
 		return "1440p"
 	case Quality2160p:
 		return "2160p"
+	case QualityBest:
+		return "best"
+	case QualityWorst:
+		return "worst"
 	default:
 		return "1080p"
 	}
 }
 
+func (q Quality) Height() int {
+	switch q {
+	case Quality360p:
+		return 360
+	case Quality480p:
+		return 480
+	case Quality720p:
+		return 720
+	case Quality1080p:
+		return 1080
+	case Quality1440p:
+		return 1440
+	case Quality2160p:
+		return 2160
+	default:
+		return 0
+	}
+}
+
+func QualityFromHeight(height int) Quality {
+	switch {
+	case height >= 2000:
+		return Quality2160p
+	case height >= 1300:
+		return Quality1440p
+	case height >= 1000:
+		return Quality1080p
+	case height >= 700:
+		return Quality720p
+	case height >= 450:
+		return Quality480p
+	default:
+		return Quality360p
+	}
+}
+
+func ParseQuality(s string) (Quality, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "360p", "360":
+		return Quality360p, nil
+	case "480p", "480":
+		return Quality480p, nil
+	case "720p", "720":
+		return Quality720p, nil
+	case "1080p", "1080":
+		return Quality1080p, nil
+	case "1440p", "1440":
+		return Quality1440p, nil
+	case "2160p", "2160", "4k":
+		return Quality2160p, nil
+	case "best":
+		return QualityBest, nil
+	case "worst":
+		return QualityWorst, nil
+	default:
+		return Quality1080p, fmt.Errorf("unknown quality: %s (valid: 360p, 480p, 720p, 1080p, 1440p, 2160p, best, worst)", s)
+	}
+}
+
+type Variant struct {
+	URL              string  `json:"url"`
+	Quality          Quality `json:"quality"`
+	Bandwidth        int     `json:"bandwidth"`
+	AverageBandwidth int     `json:"average_bandwidth,omitempty"`
+	Width            int     `json:"width"`
+	Height           int     `json:"height"`
+	Codecs           string  `json:"codecs,omitempty"`
+	FrameRate        float64 `json:"frame_rate,omitempty"`
+	Audio            string  `json:"audio,omitempty"`
+	Subtitles        string  `json:"subtitles,omitempty"`
+}
+
+type Subtitle struct {
+	URL      string `json:"url"`
+	Language string `json:"language"`
+	Label    string `json:"label"`
+	Default  bool   `json:"default,omitempty"`
+	Forced   bool   `json:"forced,omitempty"`
+	Embedded bool   `json:"embedded,omitempty"`
+}
+
+type TrackPreference struct {
+	Audio      string
+	Subtitle   string
+	ForcedOnly bool
+}
+
+func (t TrackPreference) SubtitlesOff() bool {
+	return t.Subtitle == "off"
+}
+
+var languageAliases = map[string][]string{
+	"de": {"de", "ger", "deu", "german", "deutsch"},
+	"en": {"en", "eng", "english", "englisch"},
+	"ja": {"ja", "jpn", "japanese", "japanisch"},
+	"fr": {"fr", "fre", "fra", "french"},
+	"es": {"es", "spa", "spanish"},
+	"it": {"it", "ita", "italian"},
+	"pt": {"pt", "por", "portuguese"},
+	"ru": {"ru", "rus", "russian"},
+}
+
+func NormalizeLanguageCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if base, _, found := strings.Cut(strings.ReplaceAll(code, "_", "-"), "-"); found && len(base) >= 2 && len(base) <= 3 {
+		code = base
+	}
+
+	for normalized, aliases := range languageAliases {
+		for _, alias := range aliases {
+			if code == alias {
+				return normalized
+			}
+		}
+	}
+
+	return code
+}
+
+func LanguageAliases(code string) []string {
+	normalized := NormalizeLanguageCode(code)
+	if normalized == "" {
+		return nil
+	}
+
+	var codes []string
+	for _, alias := range languageAliases[normalized] {
+		if len(alias) <= 3 {
+			codes = append(codes, alias)
+		}
+	}
+	if len(codes) == 0 {
+		codes = []string{normalized}
+	}
+	return codes
+}
+
 type StreamURL struct {
-	URL       string
-	Provider  string
-	Quality   Quality
-	ExpiresAt time.Time
+	URL       string            `json:"url"`
+	Provider  string            `json:"provider"`
+	Quality   Quality           `json:"quality"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	Headers   map[string]string `json:"headers,omitempty"`
+	Cookies   map[string]string `json:"cookies,omitempty"`
+	Subtitles []Subtitle        `json:"subtitles,omitempty"`
+	Variants  []Variant         `json:"variants,omitempty"`
+	Bandwidth int               `json:"bandwidth,omitempty"`
+	Method    string            `json:"method,omitempty"`
+	Chain     []string          `json:"chain,omitempty"`
+}
+
+func (s *StreamURL) Clone() *StreamURL {
+	clone := *s
+	clone.Headers = maps.Clone(s.Headers)
+	clone.Cookies = maps.Clone(s.Cookies)
+	clone.Subtitles = slices.Clone(s.Subtitles)
+	clone.Variants = slices.Clone(s.Variants)
+	clone.Chain = slices.Clone(s.Chain)
+	return &clone
+}
+
+func (s *StreamURL) SubtitleFor(language string) (Subtitle, bool) {
+	want := NormalizeLanguageCode(language)
+	if want == "" {
+		return Subtitle{}, false
+	}
+
+	for _, sub := range s.Subtitles {
+		if NormalizeLanguageCode(sub.Language) == want {
+			return sub, true
+		}
+	}
+
+	return Subtitle{}, false
+}
+
+func (s *StreamURL) PreferredSubtitle(tracks TrackPreference) (Subtitle, bool) {
+	if tracks.SubtitlesOff() {
+		return Subtitle{}, false
+	}
+	if !tracks.ForcedOnly {
+		return s.SubtitleFor(tracks.Subtitle)
+	}
+
+	want := NormalizeLanguageCode(tracks.Subtitle)
+	if want == "" {
+		want = NormalizeLanguageCode(tracks.Audio)
+	}
+	for _, sub := range s.Subtitles {
+		if sub.Forced && (want == "" || NormalizeLanguageCode(sub.Language) == want) {
+			return sub, true
+		}
+	}
+	return Subtitle{}, false
 }
 
 func (s *StreamURL) IsExpired() bool {
 	return time.Now().After(s.ExpiresAt)
 }
 
+func (s *StreamURL) ExpiresWithin(d time.Duration) bool {
+	return time.Now().Add(d).After(s.ExpiresAt)
+}
+
+func (s *StreamURL) Header(key string) string {
+	for k, v := range s.Headers {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+func (s *StreamURL) SetHeader(key, value string) {
+	if s.Headers == nil {
+		s.Headers = make(map[string]string)
+	}
+	for k := range s.Headers {
+		if strings.EqualFold(k, key) {
+			delete(s.Headers, k)
+		}
+	}
+	s.Headers[key] = value
+}
+
+func (s *StreamURL) CookieHeader() string {
+	if len(s.Cookies) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(s.Cookies))
+	for name := range s.Cookies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = name + "=" + s.Cookies[name]
+	}
+	return strings.Join(parts, "; ")
+}
+
+func (s *StreamURL) HeaderFields(exclude ...string) []string {
+	var fields []string
+	for key, value := range s.Headers {
+		skip := false
+		for _, ex := range exclude {
+			if strings.EqualFold(key, ex) {
+				skip = true
+				break
+			}
+		}
+		if !skip {
+			fields = append(fields, key+": "+value)
+		}
+	}
+	sort.Strings(fields)
+
+	if cookie := s.CookieHeader(); cookie != "" && s.Header("Cookie") == "" {
+		fields = append(fields, "Cookie: "+cookie)
+	}
+
+	return fields
+}
+
 type Extractor interface {
 	Extract(ctx context.Context, embeddedURL string) (*StreamURL, error)
 
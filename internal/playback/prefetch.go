@@ -0,0 +1,197 @@
+package playback
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/extractors"
+	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/providers"
+)
+
+const (
+	refreshMargin     = 2 * time.Minute
+	minValidity       = 30 * time.Second
+	minRefreshWait    = 30 * time.Second
+	maxShortRefreshes = 3
+)
+
+type Resolver struct {
+	Provider   providers.Provider
+	Extractors *extractors.System
+	Follow     extractors.RedirectFunc
+	Languages  []models.Language
+}
+
+type Prefetch struct {
+	Anime   *models.Anime
+	Episode *models.Episode
+
+	resolver Resolver
+
+	mu         sync.Mutex
+	details    *models.Episode
+	err        error
+	stream     *models.StreamURL
+	candidates []extractors.Candidate
+	index      int
+
+	loaded   chan struct{}
+	resolved chan struct{}
+	cancel   context.CancelFunc
+}
+
+func StartPrefetch(ctx context.Context, resolver Resolver, anime *models.Anime, episode *models.Episode) *Prefetch {
+	ctx, cancel := context.WithCancel(ctx)
+
+	p := &Prefetch{
+		Anime:    anime,
+		Episode:  episode,
+		resolver: resolver,
+		index:    -1,
+		loaded:   make(chan struct{}),
+		resolved: make(chan struct{}),
+		cancel:   cancel,
+	}
+
+	go p.run(ctx)
+
+	return p
+}
+
+func (p *Prefetch) run(ctx context.Context) {
+	markResolved := sync.OnceFunc(func() { close(p.resolved) })
+	defer markResolved()
+
+	log.Debug("Prefetching next episode", "anime", p.Anime.Title, "episode", p.Episode.String())
+
+	details, err := p.resolver.Provider.GetEpisode(ctx, p.Anime, p.Episode.Season, p.Episode.Episode)
+
+	p.mu.Lock()
+	p.details, p.err = details, err
+	p.mu.Unlock()
+	close(p.loaded)
+
+	if err != nil {
+		log.Debug("Failed to prefetch next episode", "episode", p.Episode.String(), "error", err)
+		return
+	}
+
+	if p.resolver.Extractors == nil {
+		return
+	}
+
+	candidates := p.resolver.Extractors.Rank(details.Providers, p.resolver.Languages)
+	if !p.resolve(ctx, candidates) {
+		return
+	}
+	markResolved()
+
+	shortLived := 0
+	for {
+		p.mu.Lock()
+		wait := time.Until(p.stream.ExpiresAt) - refreshMargin
+		p.mu.Unlock()
+
+		if wait < minRefreshWait {
+			shortLived++
+			if shortLived > maxShortRefreshes {
+				log.Debug("Prefetched stream keeps expiring quickly, no longer refreshing it", "episode", p.Episode.String())
+				return
+			}
+			wait = minRefreshWait
+		} else {
+			shortLived = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		log.Debug("Prefetched stream is about to expire, resolving again", "episode", p.Episode.String())
+		if !p.resolve(ctx, candidates) {
+			return
+		}
+	}
+}
+
+func (p *Prefetch) resolve(ctx context.Context, candidates []extractors.Candidate) bool {
+	stream, index, err := p.resolver.Extractors.Resolve(ctx, candidates, p.resolver.Follow, nil)
+	if err != nil {
+		log.Debug("Failed to prefetch stream", "episode", p.Episode.String(), "error", err)
+		return false
+	}
+
+	log.Debug("Prefetched stream",
+		"episode", p.Episode.String(),
+		"hoster", candidates[index].Hoster,
+		"quality", stream.Quality.String(),
+		"expires_at", stream.ExpiresAt)
+
+	p.mu.Lock()
+	p.stream, p.candidates, p.index = stream, candidates, index
+	p.mu.Unlock()
+
+	return true
+}
+
+func (p *Prefetch) Wait(ctx context.Context) (*models.Episode, error) {
+	select {
+	case <-p.loaded:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.details, p.err
+}
+
+func (p *Prefetch) Stream(ctx context.Context) (*models.StreamURL, extractors.Candidate, []extractors.Candidate, bool) {
+	if p == nil {
+		return nil, extractors.Candidate{}, nil, false
+	}
+
+	select {
+	case <-p.resolved:
+	case <-ctx.Done():
+		return nil, extractors.Candidate{}, nil, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stream == nil || p.stream.ExpiresWithin(minValidity) {
+		return nil, extractors.Candidate{}, nil, false
+	}
+
+	return p.stream, p.candidates[p.index], p.candidates[p.index+1:], true
+}
+
+func (p *Prefetch) Matches(anime *models.Anime, episode *models.Episode) bool {
+	return p != nil && p.Anime == anime &&
+		p.Episode.Season == episode.Season && p.Episode.Episode == episode.Episode
+}
+
+func (p *Prefetch) Cancel() {
+	if p != nil {
+		p.cancel()
+	}
+}
+
+func LoadEpisode(ctx context.Context, provider providers.Provider, anime *models.Anime, season, episode int, prefetched *Prefetch) (*models.Episode, error) {
+	if prefetched != nil {
+		details, err := prefetched.Wait(ctx)
+		if err == nil {
+			return details, nil
+		}
+		log.Debug("Prefetched episode unavailable, loading again", "season", season, "episode", episode, "error", err)
+	}
+
+	return provider.GetEpisode(ctx, anime, season, episode)
+}
@@ -0,0 +1,30 @@
+package playback
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/aniskip"
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+func LoadSkipTimes(ctx context.Context, client *aniskip.Client, anime *models.Anime, episode *models.Episode) aniskip.Intervals {
+	if anime.MALID == 0 {
+		log.Debug("No MyAnimeList id, not looking up skip times", "anime", anime.Title)
+		return nil
+	}
+
+	if episode.Season != 1 {
+		log.Debug("The MyAnimeList id only covers the first season, not looking up skip times", "episode", episode.String())
+		return nil
+	}
+
+	intervals, err := client.SkipTimes(ctx, anime.MALID, episode.Episode, 0)
+	if err != nil {
+		log.Warn("Failed to load skip times", "mal_id", anime.MALID, "episode", episode.Episode, "error", err)
+		return nil
+	}
+
+	return intervals
+}
@@ -0,0 +1,60 @@
+package playback
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/extractors"
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+type Source struct {
+	resolver Resolver
+	progress extractors.ProgressFunc
+
+	mu        sync.Mutex
+	candidate extractors.Candidate
+	remaining []extractors.Candidate
+}
+
+func NewSource(resolver Resolver, candidate extractors.Candidate, remaining []extractors.Candidate, progress extractors.ProgressFunc) *Source {
+	return &Source{
+		resolver:  resolver,
+		progress:  progress,
+		candidate: candidate,
+		remaining: remaining,
+	}
+}
+
+func (s *Source) Current() (extractors.Candidate, []extractors.Candidate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.candidate, s.remaining
+}
+
+func (s *Source) Refresh(ctx context.Context) (*models.StreamURL, error) {
+	s.mu.Lock()
+	failed, remaining := s.candidate, s.remaining
+	s.mu.Unlock()
+
+	log.Debug("Extracting stream again", "hoster", failed.Hoster, "remaining", len(remaining))
+	s.resolver.Extractors.Forget(failed)
+
+	candidates := append([]extractors.Candidate{failed}, remaining...)
+	stream, index, err := s.resolver.Extractors.Resolve(ctx, candidates, s.resolver.Follow, s.progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract stream again: %w", err)
+	}
+
+	s.mu.Lock()
+	s.candidate, s.remaining = candidates[index], candidates[index+1:]
+	s.mu.Unlock()
+
+	if index > 0 {
+		log.Info("Switched hoster during playback", "from", failed.Hoster, "to", candidates[index].Hoster)
+	}
+	return stream, nil
+}
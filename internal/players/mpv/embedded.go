@@ -0,0 +1,207 @@
+//go:build cgo
+
+package mpv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/gen2brain/go-mpv"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/players"
+)
+
+func probeEmbedded() error {
+	m := mpv.New()
+	if m == nil {
+		return fmt.Errorf("libmpv failed to create a player instance")
+	}
+	m.TerminateDestroy()
+	return nil
+}
+
+func (p *Player) playEmbedded(ctx context.Context, streamURL *models.StreamURL, opts players.Options) (players.Result, error) {
+	failed := players.Result{Reason: players.EndError}
+
+	m := mpv.New()
+	if m == nil {
+		return failed, fmt.Errorf("failed to create mpv instance")
+	}
+	defer m.TerminateDestroy()
+
+	if err := p.configureMPV(m, streamURL, opts); err != nil {
+		return failed, fmt.Errorf("failed to configure MPV: %w", err)
+	}
+
+	if err := m.Initialize(); err != nil {
+		return failed, fmt.Errorf("failed to initialize MPV: %w", err)
+	}
+
+	log.Info("Starting playback", "title", opts.Title, "provider", streamURL.Provider, "quality", streamURL.Quality.String())
+
+	if err := m.Command([]string{"loadfile", streamURL.URL}); err != nil {
+		return failed, fmt.Errorf("failed to load file: %w", err)
+	}
+
+	return p.eventLoop(ctx, m, streamURL, opts)
+}
+
+func (p *Player) configureMPV(m *mpv.Mpv, streamURL *models.StreamURL, opts players.Options) error {
+	for _, opt := range append(p.configOptions(), p.options(streamURL, opts)...) {
+		if err := m.SetOptionString(opt.name, opt.value); err != nil {
+			log.Debug("Failed to set mpv option", "option", opt.name, "error", err)
+		}
+	}
+
+	if err := m.SetOptionString("idle", "yes"); err != nil {
+		log.Debug("Failed to set mpv option", "option", "idle", "error", err)
+	}
+
+	if err := m.RequestLogMessages("info"); err != nil {
+		log.Debug("Failed to request log messages", "error", err)
+	}
+
+	for _, name := range statusProperties {
+		if err := m.ObserveProperty(0, name, propertyFormat(name)); err != nil {
+			log.Debug("Failed to observe property", "property", name, "error", err)
+		}
+	}
+
+	return nil
+}
+
+func propertyFormat(name string) mpv.Format {
+	switch name {
+	case "pause", "paused-for-cache":
+		return mpv.FormatFlag
+	case "time-pos", "duration":
+		return mpv.FormatDouble
+	default:
+		return mpv.FormatString
+	}
+}
+
+func (p *Player) loadSubtitles(m *mpv.Mpv, streamURL *models.StreamURL, tracks models.TrackPreference) {
+	for _, command := range p.subtitleCommands(streamURL, tracks) {
+		if err := m.Command(command); err != nil {
+			log.Debug("Failed to add subtitle track", "url", command[1], "error", err)
+		}
+	}
+}
+
+func (p *Player) eventLoop(ctx context.Context, m *mpv.Mpv, streamURL *models.StreamURL, opts players.Options) (players.Result, error) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	defer close(stopped)
+
+	var playbackError error
+	session := p.newSession(streamURL, opts, m.Command)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-stopped:
+			return
+		}
+		log.Debug("Context cancelled, stopping MPV")
+		if err := m.Command([]string{"quit"}); err != nil {
+			log.Debug("Failed to quit MPV gracefully", "error", err)
+		}
+		close(done)
+	}()
+
+	go func() {
+		for {
+			select {
+			case control := <-opts.Controls:
+				if command := controlCommand(control); command != nil {
+					if err := m.Command(command); err != nil {
+						log.Debug("Failed to send control to MPV", "control", control.Command.String(), "error", err)
+					}
+				}
+			case <-stopped:
+				return
+			}
+		}
+	}()
+
+	for {
+		select {
+		case <-done:
+			log.Debug("Event loop cancelled by context")
+			return session.result(players.EndQuit), nil
+		case refreshed := <-session.recovery.Results():
+			if err := session.reload(refreshed); err != nil {
+				log.Error("Failed to recover playback", "error", err)
+				return session.result(players.EndError), err
+			}
+		default:
+			session.check(ctx)
+			event := m.WaitEvent(1000)
+
+			switch event.EventID {
+			case mpv.EventPropertyChange:
+				prop := event.Property()
+				session.property(prop.Name, prop.Data)
+
+			case mpv.EventFileLoaded:
+				log.Debug("File loaded successfully")
+				p.loadSubtitles(m, session.stream, opts.Tracks)
+				if p, err := m.GetProperty("media-title", mpv.FormatString); err == nil {
+					if mediaTitle, ok := p.(string); ok {
+						log.Debug("Media title", "title", mediaTitle)
+					}
+				}
+
+			case mpv.EventStart:
+				sf := event.StartFile()
+				log.Debug("Playback started", "entry_id", sf.EntryID)
+
+			case mpv.EventEnd:
+				ef := event.EndFile()
+				log.Debug("Playback ended", "entry_id", ef.EntryID, "reason", ef.Reason)
+
+				if ef.Reason == mpv.EndFileEOF {
+					if session.recoverFromEOF(ctx) {
+						continue
+					}
+					log.Debug("Playback finished normally")
+					return session.result(players.EndEOF), nil
+				} else if ef.Reason == mpv.EndFileError {
+					if session.recoverFromError(ctx, fmt.Sprint(ef.Error)) {
+						continue
+					}
+					playbackError = fmt.Errorf("playback error: %s", ef.Error)
+					log.Error("Playback error", "error", ef.Error)
+					return session.result(players.EndError), playbackError
+				} else if ef.Reason == mpv.EndFileQuit {
+					log.Debug("User quit playback")
+					return session.result(players.EndQuit), nil
+				}
+
+			case mpv.EventShutdown:
+				log.Debug("MPV shutdown")
+				if playbackError != nil {
+					return session.result(players.EndError), playbackError
+				}
+				return session.result(players.EndQuit), nil
+
+			case mpv.EventLogMsg:
+				msg := event.LogMessage()
+				session.logMessage(msg.Level, msg.Text)
+
+			case mpv.EventNone:
+				continue
+
+			default:
+				log.Debug("Unhandled MPV event", "event_id", event.EventID)
+			}
+
+			if event.Error != nil {
+				log.Debug("Event error", "error", event.Error)
+			}
+		}
+	}
+}
@@ -0,0 +1,19 @@
+//go:build !cgo
+
+package mpv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/players"
+)
+
+func probeEmbedded() error {
+	return fmt.Errorf("this build has no libmpv support (built without cgo)")
+}
+
+func (p *Player) playEmbedded(context.Context, *models.StreamURL, players.Options) (players.Result, error) {
+	return players.Result{Reason: players.EndError}, fmt.Errorf("embedded mpv requires a cgo build")
+}
@@ -0,0 +1,243 @@
+package mpv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/players"
+	"github.com/hayasedb/hayase-cli/internal/players/mpvipc"
+)
+
+const (
+	ipcConnectTimeout = 10 * time.Second
+	ipcQuitTimeout    = 3 * time.Second
+	watchdogInterval  = time.Second
+)
+
+func (p *Player) binary() string {
+	if p.config != nil {
+		if path := p.config.GetString("mpv.path"); path != "" {
+			return path
+		}
+	}
+	return "mpv"
+}
+
+func (p *Player) playExternal(ctx context.Context, streamURL *models.StreamURL, opts players.Options) (players.Result, error) {
+	failed := players.Result{Reason: players.EndError}
+
+	if runtime.GOOS == "windows" {
+		return failed, fmt.Errorf("external mpv mode is not supported on windows")
+	}
+
+	binary, err := exec.LookPath(p.binary())
+	if err != nil {
+		return failed, fmt.Errorf("mpv binary not found: %w", err)
+	}
+
+	socketPath := filepath.Join(os.TempDir(), fmt.Sprintf("hayase-mpv-%d-%d.sock", os.Getpid(), time.Now().UnixNano()))
+	defer func() {
+		if err := os.Remove(socketPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			log.Debug("Failed to remove mpv IPC socket", "path", socketPath, "error", err)
+		}
+	}()
+
+	args := []string{"--idle=yes", "--input-ipc-server=" + socketPath}
+	for _, opt := range append(p.configOptions(), p.options(streamURL, opts)...) {
+		args = append(args, "--"+opt.name+"="+opt.value)
+	}
+
+	cmd := exec.Command(binary, args...)
+	if p.UsesTerminal() {
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	}
+	if err := cmd.Start(); err != nil {
+		return failed, fmt.Errorf("failed to start mpv: %w", err)
+	}
+
+	exited := make(chan struct{})
+	var exitErr error
+	go func() {
+		exitErr = cmd.Wait()
+		close(exited)
+	}()
+
+	dialCtx, cancel := context.WithTimeout(ctx, ipcConnectTimeout)
+	go func() {
+		select {
+		case <-exited:
+			cancel()
+		case <-dialCtx.Done():
+		}
+	}()
+	client, err := mpvipc.Dial(dialCtx, socketPath)
+	cancel()
+	if err != nil {
+		select {
+		case <-exited:
+			if exitErr != nil {
+				return failed, fmt.Errorf("mpv exited before accepting commands: %w", exitErr)
+			}
+			return failed, fmt.Errorf("mpv exited before accepting commands")
+		default:
+		}
+		stopProcess(cmd, exited)
+		return failed, fmt.Errorf("failed to connect to mpv: %w", err)
+	}
+	defer func() {
+		if err := client.Close(); err != nil {
+			log.Debug("Failed to close mpv IPC connection", "error", err)
+		}
+	}()
+
+	if err := client.RequestLogMessages(ctx, "info"); err != nil {
+		log.Debug("Failed to request log messages", "error", err)
+	}
+
+	for i, name := range statusProperties {
+		if err := client.ObserveProperty(ctx, int64(i+1), name); err != nil {
+			log.Debug("Failed to observe property", "property", name, "error", err)
+		}
+	}
+
+	log.Info("Starting playback", "title", opts.Title, "provider", streamURL.Provider, "quality", streamURL.Quality.String(), "mode", ModeExternal)
+
+	if _, err := client.Command(ctx, "loadfile", streamURL.URL); err != nil {
+		stopProcess(cmd, exited)
+		return failed, fmt.Errorf("failed to load file: %w", err)
+	}
+
+	result, err := p.externalEventLoop(ctx, client, streamURL, opts)
+
+	quitCtx, cancelQuit := context.WithTimeout(context.Background(), ipcQuitTimeout)
+	defer cancelQuit()
+	if _, quitErr := client.Command(quitCtx, "quit"); quitErr != nil && !errors.Is(quitErr, mpvipc.ErrClosed) {
+		log.Debug("Failed to quit MPV gracefully", "error", quitErr)
+	}
+	stopProcess(cmd, exited)
+
+	return result, err
+}
+
+func (p *Player) externalEventLoop(ctx context.Context, client *mpvipc.Client, streamURL *models.StreamURL, opts players.Options) (players.Result, error) {
+	session := p.newSession(streamURL, opts, func(command []string) error {
+		_, err := client.Command(ctx, commandArgs(command)...)
+		return err
+	})
+
+	ticker := time.NewTicker(watchdogInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Event loop cancelled by context")
+			return session.result(players.EndQuit), nil
+
+		case control := <-opts.Controls:
+			command := controlCommand(control)
+			if command == nil {
+				continue
+			}
+			if err := session.command(command); err != nil {
+				log.Debug("Failed to send control to MPV", "control", control.Command.String(), "error", err)
+			}
+
+		case <-ticker.C:
+			session.check(ctx)
+
+		case refreshed := <-session.recovery.Results():
+			if err := session.reload(refreshed); err != nil {
+				log.Error("Failed to recover playback", "error", err)
+				return session.result(players.EndError), err
+			}
+
+		case event, ok := <-client.Events():
+			if !ok {
+				log.Debug("MPV IPC connection closed")
+				return session.result(players.EndQuit), nil
+			}
+
+			switch event.Event {
+			case "property-change":
+				session.property(event.Name, event.Data)
+
+			case "file-loaded":
+				log.Debug("File loaded successfully")
+				for _, command := range p.subtitleCommands(session.stream, opts.Tracks) {
+					if err := session.command(command); err != nil {
+						log.Debug("Failed to add subtitle track", "url", command[1], "error", err)
+					}
+				}
+				if mediaTitle, err := client.GetProperty(ctx, "media-title"); err == nil {
+					log.Debug("Media title", "title", mediaTitle)
+				}
+
+			case "start-file":
+				log.Debug("Playback started", "entry_id", event.PlaylistEntryID)
+
+			case "end-file":
+				log.Debug("Playback ended", "entry_id", event.PlaylistEntryID, "reason", event.Reason)
+
+				switch event.Reason {
+				case "eof":
+					if session.recoverFromEOF(ctx) {
+						continue
+					}
+					log.Debug("Playback finished normally")
+					return session.result(players.EndEOF), nil
+				case "error":
+					if session.recoverFromError(ctx, event.FileError) {
+						continue
+					}
+					log.Error("Playback error", "error", event.FileError)
+					return session.result(players.EndError), fmt.Errorf("playback error: %s", event.FileError)
+				case "quit":
+					log.Debug("User quit playback")
+					return session.result(players.EndQuit), nil
+				}
+
+			case "shutdown":
+				log.Debug("MPV shutdown")
+				return session.result(players.EndQuit), nil
+
+			case "log-message":
+				session.logMessage(event.Level, event.Text)
+
+			default:
+				log.Debug("Unhandled MPV event", "event", event.Event)
+			}
+		}
+	}
+}
+
+func commandArgs(command []string) []any {
+	args := make([]any, len(command))
+	for i, arg := range command {
+		args[i] = arg
+	}
+	return args
+}
+
+func stopProcess(cmd *exec.Cmd, exited <-chan struct{}) {
+	select {
+	case <-exited:
+		return
+	case <-time.After(ipcQuitTimeout):
+	}
+
+	log.Debug("MPV did not exit, killing process")
+	if err := cmd.Process.Kill(); err != nil {
+		log.Debug("Failed to kill mpv", "error", err)
+	}
+	<-exited
+}
@@ -3,25 +3,38 @@ package mpv
 import (
 	"context"
 	"fmt"
-	"runtime"
+	"os/exec"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
-	"github.com/gen2brain/go-mpv"
 
 	"github.com/hayasedb/hayase-cli/internal/models"
 	"github.com/hayasedb/hayase-cli/internal/players"
+	"github.com/hayasedb/hayase-cli/internal/storage"
+)
+
+const defaultUserAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:98.0) Gecko/20100101 Firefox/98.0"
+
+const (
+	ModeAuto     = "auto"
+	ModeEmbedded = "embedded"
+	ModeExternal = "external"
 )
 
 type Player struct {
-	name string
-	mpv  *mpv.Mpv
+	name   string
+	config *storage.Config
 }
 
-func New() players.Player {
+func New(config *storage.Config) players.Player {
 	return &Player{
-		name: "MPV",
+		name:   "MPV",
+		config: config,
 	}
 }
 
@@ -29,202 +42,274 @@ func (p *Player) Name() string {
 	return p.name
 }
 
-func (p *Player) IsAvailable() bool {
-	return true
+func (p *Player) CheckAvailability() error {
+	switch p.Mode() {
+	case ModeEmbedded:
+		if err := embeddedAvailable(); err != nil {
+			return fmt.Errorf("embedded mode: %w", err)
+		}
+		return nil
+	default:
+		if _, err := exec.LookPath(p.binary()); err != nil {
+			if p.configuredMode() == ModeAuto {
+				return fmt.Errorf("mpv binary %q not found on PATH and embedded mode is unavailable: %w", p.binary(), embeddedAvailable())
+			}
+			return fmt.Errorf("external mode: mpv binary %q not found on PATH", p.binary())
+		}
+		return nil
+	}
 }
 
-func (p *Player) Play(ctx context.Context, streamURL *models.StreamURL, title string) error {
+func (p *Player) configuredMode() string {
+	if p.config == nil {
+		return ModeAuto
+	}
+
+	switch mode := strings.ToLower(p.config.GetString("mpv.mode")); mode {
+	case ModeEmbedded, ModeExternal:
+		return mode
+	default:
+		return ModeAuto
+	}
+}
+
+func (p *Player) Mode() string {
+	switch mode := p.configuredMode(); mode {
+	case ModeEmbedded, ModeExternal:
+		return mode
+	default:
+		if embeddedAvailable() == nil {
+			return ModeEmbedded
+		}
+		return ModeExternal
+	}
+}
+
+var (
+	embeddedOnce  sync.Once
+	embeddedError error
+)
+
+func embeddedAvailable() error {
+	embeddedOnce.Do(func() {
+		embeddedError = probeEmbedded()
+	})
+	return embeddedError
+}
+
+func (p *Player) Play(ctx context.Context, streamURL *models.StreamURL, opts players.Options) (players.Result, error) {
+	failed := players.Result{Reason: players.EndError}
+
 	if streamURL.IsExpired() {
-		return fmt.Errorf("stream URL has expired at %v (current time: %v)",
+		return failed, fmt.Errorf("stream URL has expired at %v (current time: %v)",
 			streamURL.ExpiresAt, time.Now())
 	}
 
 	if streamURL.URL == "" {
-		return fmt.Errorf("stream URL is empty")
+		return failed, fmt.Errorf("stream URL is empty")
 	}
 
 	if !strings.HasPrefix(streamURL.URL, "http") {
-		return fmt.Errorf("invalid stream URL format: %s", streamURL.URL)
+		return failed, fmt.Errorf("invalid stream URL format: %s", streamURL.URL)
 	}
 
-	m := mpv.New()
-	if m == nil {
-		return fmt.Errorf("failed to create mpv instance")
+	if p.UsesTerminal() {
+		log.Info("Rendering video in the terminal", "vo", p.TerminalOutput(), "configured", p.configuredVO())
 	}
-	defer m.TerminateDestroy()
-
-	p.mpv = m
 
-	if err := p.configureMPV(m, title); err != nil {
-		return fmt.Errorf("failed to configure MPV: %w", err)
+	if p.Mode() == ModeExternal {
+		return p.playExternal(ctx, streamURL, opts)
 	}
 
-	if err := m.Initialize(); err != nil {
-		return fmt.Errorf("failed to initialize MPV: %w", err)
+	if err := embeddedAvailable(); err != nil {
+		return failed, fmt.Errorf("embedded mpv is not available: %w", err)
 	}
 
-	log.Info("Starting playback", "title", title, "provider", streamURL.Provider, "quality", streamURL.Quality.String())
+	return p.playEmbedded(ctx, streamURL, opts)
+}
+
+type option struct {
+	name  string
+	value string
+}
+
+func (p *Player) options(streamURL *models.StreamURL, opts players.Options) []option {
+	list := p.defaultOptions()
 
-	if err := m.Command([]string{"loadfile", streamURL.URL}); err != nil {
-		return fmt.Errorf("failed to load file: %w", err)
+	overrides := p.userOptions()
+	for _, name := range sortedKeys(overrides) {
+		if value := overrides[name]; value != "" {
+			list = setOption(list, name, value)
+		} else {
+			list = removeOption(list, name)
+		}
 	}
 
-	return p.eventLoop(ctx, m)
-}
+	userAgent := streamURL.Header("User-Agent")
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	list = setOption(list, "user-agent", userAgent)
 
-func (p *Player) configureMPV(m *mpv.Mpv, title string) error {
-	if err := m.SetOptionString("user-agent", "Mozilla/5.0 (X11; Linux x86_64; rv:98.0) Gecko/20100101 Firefox/98.0"); err != nil {
-		log.Debug("Failed to set user-agent", "error", err)
+	if referrer := streamURL.Header("Referer"); referrer != "" {
+		list = setOption(list, "referrer", referrer)
 	}
 
-	if err := m.SetOptionString("referrer", "https://aniworld.to"); err != nil {
-		log.Debug("Failed to set referrer", "error", err)
+	if streamURL.Bandwidth > 0 {
+		list = setOption(list, "hls-bitrate", strconv.Itoa(streamURL.Bandwidth))
 	}
 
-	if err := m.SetPropertyString("input-default-bindings", "yes"); err != nil {
-		log.Debug("Failed to set input-default-bindings", "error", err)
+	if fields := streamURL.HeaderFields("User-Agent", "Referer"); len(fields) > 0 {
+		list = setOption(list, "http-header-fields", joinOptionList(fields))
 	}
 
-	if err := m.SetOptionString("input-vo-keyboard", "yes"); err != nil {
-		log.Debug("Failed to set input-vo-keyboard", "error", err)
+	for _, opt := range trackOptions(opts.Tracks) {
+		list = setOption(list, opt.name, opt.value)
 	}
 
-	if err := m.SetOption("osc", mpv.FormatFlag, true); err != nil {
-		log.Debug("Failed to enable OSC", "error", err)
+	if opts.Title != "" {
+		list = setOption(list, "force-media-title", opts.Title)
 	}
 
-	if title != "" {
-		if err := m.SetOptionString("force-media-title", title); err != nil {
-			log.Debug("Failed to set media title", "error", err)
-		}
+	if opts.Start > 0 {
+		list = setOption(list, "start", strconv.FormatFloat(opts.Start.Seconds(), 'f', 1, 64))
 	}
 
-	if err := m.SetOption("fs", mpv.FormatFlag, true); err != nil {
-		log.Debug("Failed to enable fullscreen", "error", err)
+	if !p.UsesTerminal() {
+		return setOption(list, "terminal", "no")
 	}
 
-	if err := m.SetOptionString("hwdec", "auto"); err != nil {
-		log.Debug("Failed to set hardware decoding", "error", err)
+	for _, opt := range terminalOptions(p.TerminalOutput()) {
+		list = setOption(list, opt.name, opt.value)
 	}
+	return list
+}
 
-	if err := m.SetOptionString("vo", "gpu"); err != nil {
-		log.Debug("Failed to set video output", "error", err)
+func (p *Player) defaultOptions() []option {
+	list := []option{
+		{"input-default-bindings", "yes"},
+		{"input-vo-keyboard", "yes"},
+		{"osc", "yes"},
+		{"really-quiet", "yes"},
+	}
+	if p.userConfig() {
+		return list
 	}
 
-	switch runtime.GOOS {
-	case "linux":
-		if err := m.SetOptionString("ao", "pulse"); err != nil {
-			log.Debug("Failed to set audio output to pulse", "error", err)
-		}
-	case "darwin":
-		if err := m.SetOptionString("ao", "coreaudio"); err != nil {
-			log.Debug("Failed to set audio output to coreaudio", "error", err)
-		}
+	return append(list,
+		option{"fs", "yes"},
+		option{"hwdec", "auto"},
+		option{"cache", "yes"},
+		option{"network-timeout", "30"},
+	)
+}
+
+func (p *Player) userOptions() map[string]string {
+	if p.config == nil {
+		return nil
 	}
+	return p.config.GetMPVOptions()
+}
 
-	if err := m.SetOption("cache", mpv.FormatFlag, true); err != nil {
-		log.Debug("Failed to enable cache", "error", err)
+func (p *Player) userConfig() bool {
+	return p.config != nil && (p.config.GetBool("mpv.user_config") || p.configDir() != "")
+}
+
+func (p *Player) configDir() string {
+	if p.config == nil {
+		return ""
 	}
+	return p.config.GetString("mpv.config_dir")
+}
 
-	if err := m.SetOption("network-timeout", mpv.FormatInt64, int64(30)); err != nil {
-		log.Debug("Failed to set network timeout", "error", err)
+func (p *Player) configOptions() []option {
+	if !p.userConfig() {
+		return []option{{"config", "no"}}
 	}
 
-	if err := m.SetOption("really-quiet", mpv.FormatFlag, true); err != nil {
-		log.Debug("Failed to enable quiet mode", "error", err)
+	list := []option{{"config", "yes"}, {"load-scripts", "yes"}}
+	if dir := p.configDir(); dir != "" {
+		list = append(list, option{"config-dir", dir})
 	}
+	return list
+}
 
-	if err := m.SetOption("no-terminal", mpv.FormatFlag, true); err != nil {
-		log.Debug("Failed to disable terminal", "error", err)
+func setOption(list []option, name, value string) []option {
+	for i := range list {
+		if list[i].name == name {
+			list[i].value = value
+			return list
+		}
 	}
+	return append(list, option{name, value})
+}
 
-	if err := m.RequestLogMessages("info"); err != nil {
-		log.Debug("Failed to request log messages", "error", err)
+func removeOption(list []option, name string) []option {
+	return slices.DeleteFunc(list, func(opt option) bool {
+		return opt.name == name
+	})
+}
+
+func sortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
 	}
+	sort.Strings(keys)
+	return keys
+}
 
-	if err := m.ObserveProperty(0, "pause", mpv.FormatFlag); err != nil {
-		log.Debug("Failed to observe pause property", "error", err)
+func trackOptions(tracks models.TrackPreference) []option {
+	var list []option
+
+	if tracks.Audio != "" {
+		list = append(list, option{"alang", strings.Join(models.LanguageAliases(tracks.Audio), ",")})
 	}
 
-	return nil
+	switch {
+	case tracks.SubtitlesOff():
+		list = append(list, option{"sid", "no"})
+	case tracks.ForcedOnly:
+		list = append(list,
+			option{"subs-fallback", "no"},
+			option{"subs-fallback-forced", "always"},
+		)
+	case tracks.Subtitle != "":
+		list = append(list, option{"slang", strings.Join(models.LanguageAliases(tracks.Subtitle), ",")})
+	}
+
+	return list
 }
 
-func (p *Player) eventLoop(ctx context.Context, m *mpv.Mpv) error {
-	done := make(chan struct{})
-	var playbackError error
+func (p *Player) subtitleCommands(streamURL *models.StreamURL, tracks models.TrackPreference) [][]string {
+	preferred, hasPreferred := streamURL.PreferredSubtitle(tracks)
 
-	go func() {
-		<-ctx.Done()
-		log.Debug("Context cancelled, stopping MPV")
-		if err := m.Command([]string{"quit"}); err != nil {
-			log.Debug("Failed to quit MPV gracefully", "error", err)
+	var commands [][]string
+	for _, sub := range streamURL.Subtitles {
+		if sub.URL == "" || sub.Embedded {
+			continue
 		}
-		close(done)
-	}()
-
-	for {
-		select {
-		case <-done:
-			log.Debug("Event loop cancelled by context")
-			return nil
-		default:
-			event := m.WaitEvent(1000)
-
-			switch event.EventID {
-			case mpv.EventPropertyChange:
-				prop := event.Property()
-				if prop.Name == "pause" {
-					if paused, ok := prop.Data.(int); ok {
-						log.Debug("Pause state changed", "paused", paused == 1)
-					}
-				}
-
-			case mpv.EventFileLoaded:
-				log.Debug("File loaded successfully")
-				if p, err := m.GetProperty("media-title", mpv.FormatString); err == nil {
-					if mediaTitle, ok := p.(string); ok {
-						log.Debug("Media title", "title", mediaTitle)
-					}
-				}
-
-			case mpv.EventStart:
-				sf := event.StartFile()
-				log.Debug("Playback started", "entry_id", sf.EntryID)
-
-			case mpv.EventEnd:
-				ef := event.EndFile()
-				log.Debug("Playback ended", "entry_id", ef.EntryID, "reason", ef.Reason)
-
-				if ef.Reason == mpv.EndFileEOF {
-					log.Debug("Playback finished normally")
-					return nil
-				} else if ef.Reason == mpv.EndFileError {
-					playbackError = fmt.Errorf("playback error: %s", ef.Error)
-					log.Error("Playback error", "error", ef.Error)
-					return playbackError
-				} else if ef.Reason == mpv.EndFileQuit {
-					log.Debug("User quit playback")
-					return nil
-				}
-
-			case mpv.EventShutdown:
-				log.Debug("MPV shutdown")
-				return playbackError
-
-			case mpv.EventLogMsg:
-				msg := event.LogMessage()
-				log.Debug("MPV log", "level", msg.Level, "text", strings.TrimSpace(msg.Text))
-
-			case mpv.EventNone:
-				continue
-
-			default:
-				log.Debug("Unhandled MPV event", "event_id", event.EventID)
-			}
 
-			if event.Error != nil {
-				log.Debug("Event error", "error", event.Error)
-			}
+		label := sub.Label
+		if label == "" {
+			label = sub.Language
 		}
+
+		flag := "auto"
+		if hasPreferred && sub.URL == preferred.URL {
+			flag = "select"
+		}
+
+		commands = append(commands, []string{"sub-add", sub.URL, flag, label, sub.Language})
+	}
+
+	return commands
+}
+
+func joinOptionList(items []string) string {
+	escaped := make([]string, len(items))
+	for i, item := range items {
+		escaped[i] = strings.ReplaceAll(item, ",", `\,`)
 	}
+	return strings.Join(escaped, ",")
 }
@@ -0,0 +1,154 @@
+package mpv
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/players"
+)
+
+type session struct {
+	player   *Player
+	opts     players.Options
+	stream   *models.StreamURL
+	command  func([]string) error
+	tracker  statusTracker
+	watchdog *players.Watchdog
+	recovery *players.Recovery
+}
+
+func (p *Player) newSession(streamURL *models.StreamURL, opts players.Options, command func([]string) error) *session {
+	return &session{
+		player:   p,
+		opts:     opts,
+		stream:   streamURL,
+		command:  command,
+		watchdog: players.NewWatchdog(players.DefaultStallTimeout),
+		recovery: players.NewRecovery(opts.Refresh),
+	}
+}
+
+func (s *session) result(reason players.EndReason) players.Result {
+	return players.Result{
+		Reason:   reason,
+		Position: s.tracker.status.Position,
+		Duration: s.tracker.status.Duration,
+	}
+}
+
+func (s *session) property(name string, value any) {
+	if name == "pause" {
+		log.Debug("Pause state changed", "paused", flagValue(value))
+	}
+	if s.tracker.apply(name, value) {
+		s.opts.Notify(s.tracker.status)
+	}
+	s.watchdog.Observe(s.tracker.status)
+}
+
+func (s *session) logMessage(level, text string) {
+	text = strings.TrimSpace(text)
+	log.Debug("MPV log", "level", level, "text", text)
+
+	if reason, ok := httpFailure(text); ok {
+		log.Warn("Stream request failed during playback", "reason", reason, "position", players.FormatPosition(s.tracker.status.Position))
+		s.watchdog.Fail(reason)
+	}
+}
+
+func (s *session) check(ctx context.Context) {
+	if s.recovery.Active() {
+		return
+	}
+	if reason, ok := s.watchdog.Check(); ok && !s.recover(ctx, reason) {
+		log.Debug("Cannot recover stalled playback", "reason", reason)
+		s.watchdog.Reset()
+	}
+}
+
+func (s *session) recoverFromEOF(ctx context.Context) bool {
+	if s.recovery.Active() {
+		return true
+	}
+	failure := s.watchdog.Failure()
+	return failure != "" && s.recover(ctx, failure)
+}
+
+func (s *session) recoverFromError(ctx context.Context, fileError string) bool {
+	if s.recovery.Active() {
+		return true
+	}
+	return s.tracker.status.Position > 0 && s.recover(ctx, "playback error: "+fileError)
+}
+
+func (s *session) recover(ctx context.Context, reason string) bool {
+	if !s.recovery.Start(ctx, reason, s.tracker.status.Position) {
+		return false
+	}
+
+	log.Warn("Playback interrupted, extracting the stream again", "reason", reason, "position", players.FormatPosition(s.tracker.status.Position))
+	s.setRecovering(true)
+	return true
+}
+
+func (s *session) reload(refreshed players.RefreshResult) error {
+	s.recovery.Finish()
+	s.setRecovering(false)
+
+	if refreshed.Err != nil {
+		return fmt.Errorf("stream failed (%s) and could not be extracted again: %w", s.recovery.Reason, refreshed.Err)
+	}
+
+	s.stream = refreshed.Stream
+	s.watchdog.Reset()
+
+	log.Info("Reloading stream", "provider", s.stream.Provider, "quality", s.stream.Quality.String(), "position", players.FormatPosition(s.recovery.Position))
+
+	for _, command := range reloadCommands(s.stream, s.recovery.Position) {
+		if err := s.command(command); err != nil {
+			return fmt.Errorf("failed to reload stream: %w", err)
+		}
+	}
+	return nil
+}
+
+func (s *session) setRecovering(recovering bool) {
+	s.tracker.status.Recovering = recovering
+	s.tracker.reported = s.tracker.status
+	s.opts.Notify(s.tracker.status)
+}
+
+func reloadCommands(streamURL *models.StreamURL, position time.Duration) [][]string {
+	userAgent := streamURL.Header("User-Agent")
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+
+	commands := [][]string{
+		{"set", "start", strconv.FormatFloat(position.Seconds(), 'f', 1, 64)},
+		{"set", "user-agent", userAgent},
+		{"set", "referrer", streamURL.Header("Referer")},
+		{"set", "http-header-fields", joinOptionList(streamURL.HeaderFields("User-Agent", "Referer"))},
+	}
+
+	if streamURL.Bandwidth > 0 {
+		commands = append(commands, []string{"set", "hls-bitrate", strconv.Itoa(streamURL.Bandwidth)})
+	}
+
+	return append(commands, []string{"loadfile", streamURL.URL, "replace"})
+}
+
+func httpFailure(text string) (string, bool) {
+	for _, code := range []string{"403", "410"} {
+		if strings.Contains(text, "HTTP error "+code) || strings.Contains(text, "Server returned "+code) {
+			return "HTTP " + code, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,127 @@
+package mpv
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/hayasedb/hayase-cli/internal/players"
+)
+
+var statusProperties = []string{
+	"pause",
+	"time-pos",
+	"duration",
+	"paused-for-cache",
+	"sid",
+	"aid",
+	"current-tracks/sub/lang",
+	"current-tracks/audio/lang",
+}
+
+type statusTracker struct {
+	status    players.Status
+	reported  players.Status
+	sid       string
+	aid       string
+	subLang   string
+	audioLang string
+}
+
+func (t *statusTracker) apply(name string, value any) bool {
+	switch name {
+	case "pause":
+		t.status.Paused = flagValue(value)
+	case "paused-for-cache":
+		t.status.Buffering = flagValue(value)
+	case "time-pos":
+		if seconds, ok := value.(float64); ok {
+			t.status.Position = players.Seconds(seconds)
+		}
+	case "duration":
+		if seconds, ok := value.(float64); ok {
+			t.status.Duration = players.Seconds(seconds)
+		}
+	case "sid":
+		t.sid = trackValue(value)
+	case "aid":
+		t.aid = trackValue(value)
+	case "current-tracks/sub/lang":
+		t.subLang, _ = value.(string)
+	case "current-tracks/audio/lang":
+		t.audioLang, _ = value.(string)
+	default:
+		return false
+	}
+
+	t.status.Subtitle = trackLabel(t.sid, t.subLang)
+	t.status.Audio = trackLabel(t.aid, t.audioLang)
+
+	current, reported := t.status, t.reported
+	current.Position = current.Position.Truncate(time.Second)
+	reported.Position = reported.Position.Truncate(time.Second)
+	if current == reported {
+		return false
+	}
+
+	t.reported = t.status
+	return true
+}
+
+func flagValue(value any) bool {
+	switch v := value.(type) {
+	case bool:
+		return v
+	case int:
+		return v == 1
+	case string:
+		return v == "yes"
+	default:
+		return false
+	}
+}
+
+func trackValue(value any) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.Itoa(int(v))
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		if !v {
+			return "no"
+		}
+	}
+	return ""
+}
+
+func trackLabel(id, lang string) string {
+	switch {
+	case id == "" || id == "auto":
+		return ""
+	case id == "no":
+		return "off"
+	case lang != "":
+		return lang
+	default:
+		return "#" + id
+	}
+}
+
+func controlCommand(control players.Control) []string {
+	switch control.Command {
+	case players.CommandTogglePause:
+		return []string{"cycle", "pause"}
+	case players.CommandSeek:
+		return []string{"seek", strconv.FormatFloat(control.Offset.Seconds(), 'f', 1, 64), "relative"}
+	case players.CommandSeekTo:
+		return []string{"seek", strconv.FormatFloat(control.Offset.Seconds(), 'f', 1, 64), "absolute"}
+	case players.CommandCycleSubtitle:
+		return []string{"cycle", "sub"}
+	case players.CommandCycleAudio:
+		return []string{"cycle", "audio"}
+	default:
+		return nil
+	}
+}
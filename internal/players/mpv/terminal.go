@@ -0,0 +1,85 @@
+package mpv
+
+import (
+	"os"
+	"runtime"
+	"strings"
+)
+
+const (
+	VOAuto     = "auto"
+	VOWindow   = "window"
+	VOTerminal = "terminal"
+)
+
+var terminalOutputs = []string{"kitty", "sixel", "tct"}
+
+func (p *Player) configuredVO() string {
+	if p.config == nil {
+		return VOAuto
+	}
+
+	switch vo := strings.ToLower(p.config.GetString("mpv.vo")); vo {
+	case VOWindow, VOTerminal:
+		return vo
+	default:
+		return VOAuto
+	}
+}
+
+func (p *Player) UsesTerminal() bool {
+	switch p.configuredVO() {
+	case VOTerminal:
+		return true
+	case VOWindow:
+		return false
+	default:
+		return Headless()
+	}
+}
+
+func (p *Player) TerminalOutput() string {
+	if p.config != nil {
+		vo := strings.ToLower(p.config.GetString("mpv.terminal_vo"))
+		for _, output := range terminalOutputs {
+			if vo == output {
+				return vo
+			}
+		}
+	}
+	return DetectTerminalOutput()
+}
+
+func Headless() bool {
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return false
+	default:
+		return os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == ""
+	}
+}
+
+func DetectTerminalOutput() string {
+	term := strings.ToLower(os.Getenv("TERM"))
+	program := strings.ToLower(os.Getenv("TERM_PROGRAM"))
+
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty",
+		program == "ghostty", program == "wezterm":
+		return "kitty"
+	case strings.Contains(term, "sixel"), strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "mlterm"),
+		strings.HasPrefix(term, "contour"), strings.HasPrefix(term, "yaft"), program == "iterm.app":
+		return "sixel"
+	default:
+		return "tct"
+	}
+}
+
+func terminalOptions(vo string) []option {
+	return []option{
+		{"vo", vo},
+		{"terminal", "yes"},
+		{"input-terminal", "yes"},
+		{"fs", "no"},
+	}
+}
@@ -0,0 +1,313 @@
+package mpvipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	dialInterval = 50 * time.Millisecond
+	eventBuffer  = 64
+	maxLineSize  = 1 << 20
+)
+
+var ErrClosed = errors.New("mpv IPC connection closed")
+
+type Event struct {
+	Event           string `json:"event"`
+	ID              int64  `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	Data            any    `json:"data,omitempty"`
+	Reason          string `json:"reason,omitempty"`
+	FileError       string `json:"file_error,omitempty"`
+	PlaylistEntryID int64  `json:"playlist_entry_id,omitempty"`
+	Prefix          string `json:"prefix,omitempty"`
+	Level           string `json:"level,omitempty"`
+	Text            string `json:"text,omitempty"`
+}
+
+type message struct {
+	Event
+	RequestID *int64 `json:"request_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+type reply struct {
+	data any
+	err  error
+}
+
+type Client struct {
+	conn net.Conn
+
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	nextID  int64
+	pending map[int64]chan reply
+	err     error
+
+	queueMu  sync.Mutex
+	queue    []Event
+	queued   chan struct{}
+	finished chan struct{}
+
+	events  chan Event
+	done    chan struct{}
+	closing chan struct{}
+	once    sync.Once
+}
+
+func Dial(ctx context.Context, socketPath string) (*Client, error) {
+	var dialer net.Dialer
+
+	for {
+		conn, err := dialer.DialContext(ctx, "unix", socketPath)
+		if err == nil {
+			return newClient(conn), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to connect to mpv IPC socket %s: %w", socketPath, err)
+		case <-time.After(dialInterval):
+		}
+	}
+}
+
+func newClient(conn net.Conn) *Client {
+	c := &Client{
+		conn:     conn,
+		pending:  make(map[int64]chan reply),
+		queued:   make(chan struct{}, 1),
+		finished: make(chan struct{}),
+		events:   make(chan Event, eventBuffer),
+		done:     make(chan struct{}),
+		closing:  make(chan struct{}),
+	}
+
+	go c.readLoop()
+	go c.eventLoop()
+
+	return c
+}
+
+func (c *Client) Events() <-chan Event {
+	return c.events
+}
+
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Client) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *Client) Close() error {
+	c.once.Do(func() { close(c.closing) })
+	return c.conn.Close()
+}
+
+func (c *Client) Command(ctx context.Context, args ...any) (any, error) {
+	c.mu.Lock()
+	if c.err != nil {
+		err := c.err
+		c.mu.Unlock()
+		return nil, err
+	}
+	c.nextID++
+	id := c.nextID
+	ch := make(chan reply, 1)
+	c.pending[id] = ch
+	c.mu.Unlock()
+
+	data, err := json.Marshal(map[string]any{"command": args, "request_id": id})
+	if err != nil {
+		c.forget(id)
+		return nil, fmt.Errorf("failed to encode mpv command: %w", err)
+	}
+
+	c.writeMu.Lock()
+	_, err = c.conn.Write(append(data, '\n'))
+	c.writeMu.Unlock()
+	if err != nil {
+		c.forget(id)
+		return nil, fmt.Errorf("failed to send mpv command: %w", err)
+	}
+
+	select {
+	case r := <-ch:
+		return r.data, r.err
+	case <-ctx.Done():
+		c.forget(id)
+		return nil, ctx.Err()
+	}
+}
+
+func (c *Client) SetProperty(ctx context.Context, name string, value any) error {
+	_, err := c.Command(ctx, "set_property", name, value)
+	return err
+}
+
+func (c *Client) GetProperty(ctx context.Context, name string) (any, error) {
+	return c.Command(ctx, "get_property", name)
+}
+
+func (c *Client) ObserveProperty(ctx context.Context, id int64, name string) error {
+	_, err := c.Command(ctx, "observe_property", id, name)
+	return err
+}
+
+func (c *Client) RequestLogMessages(ctx context.Context, level string) error {
+	_, err := c.Command(ctx, "request_log_messages", level)
+	return err
+}
+
+func (c *Client) forget(id int64) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+func (c *Client) readLoop() {
+	scanner := bufio.NewScanner(c.conn)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	for scanner.Scan() {
+		var msg message
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			log.Debug("Ignoring malformed mpv IPC message", "error", err)
+			continue
+		}
+
+		if msg.RequestID != nil && msg.Event.Event == "" {
+			c.deliver(*msg.RequestID, msg)
+			continue
+		}
+
+		if msg.Event.Event != "" {
+			c.enqueue(msg.Event)
+		}
+	}
+
+	err := scanner.Err()
+	if err == nil {
+		err = ErrClosed
+	}
+	c.shutdown(err)
+}
+
+func (c *Client) enqueue(event Event) {
+	c.queueMu.Lock()
+	switch event.Event {
+	case "log-message":
+		if len(c.queue) >= eventBuffer {
+			c.queueMu.Unlock()
+			return
+		}
+		c.queue = append(c.queue, event)
+	case "property-change":
+		replaced := false
+		for i := range c.queue {
+			if c.queue[i].Event == "property-change" && c.queue[i].Name == event.Name {
+				c.queue[i] = event
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			c.queue = append(c.queue, event)
+		}
+	default:
+		c.queue = append(c.queue, event)
+	}
+	c.queueMu.Unlock()
+
+	select {
+	case c.queued <- struct{}{}:
+	default:
+	}
+}
+
+func (c *Client) dequeue() (Event, bool) {
+	c.queueMu.Lock()
+	defer c.queueMu.Unlock()
+
+	if len(c.queue) == 0 {
+		return Event{}, false
+	}
+	event := c.queue[0]
+	c.queue = c.queue[1:]
+	return event, true
+}
+
+func (c *Client) eventLoop() {
+	defer close(c.events)
+
+	for {
+		event, ok := c.dequeue()
+		if !ok {
+			select {
+			case <-c.queued:
+				continue
+			case <-c.finished:
+				if event, ok = c.dequeue(); !ok {
+					return
+				}
+			case <-c.closing:
+				return
+			}
+		}
+
+		select {
+		case c.events <- event:
+		case <-c.closing:
+			return
+		}
+	}
+}
+
+func (c *Client) deliver(id int64, msg message) {
+	c.mu.Lock()
+	ch, exists := c.pending[id]
+	delete(c.pending, id)
+	c.mu.Unlock()
+
+	if !exists {
+		return
+	}
+
+	if msg.Error != "" && msg.Error != "success" {
+		ch <- reply{err: fmt.Errorf("mpv command failed: %s", msg.Error)}
+		return
+	}
+	ch <- reply{data: msg.Data}
+}
+
+func (c *Client) shutdown(err error) {
+	c.mu.Lock()
+	if c.err == nil {
+		c.err = err
+	}
+	pending := c.pending
+	c.pending = make(map[int64]chan reply)
+	c.mu.Unlock()
+
+	for _, ch := range pending {
+		ch <- reply{err: err}
+	}
+
+	close(c.finished)
+	close(c.done)
+}
@@ -3,21 +3,50 @@ package players
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
 
 	"github.com/hayasedb/hayase-cli/internal/models"
 )
 
+type Options struct {
+	Title    string
+	Start    time.Duration
+	Tracks   models.TrackPreference
+	Status   func(Status)
+	Controls <-chan Control
+	Refresh  RefreshFunc
+}
+
+func (o Options) Notify(status Status) {
+	if o.Status != nil {
+		o.Status(status)
+	}
+}
+
 type Player interface {
 	Name() string
 
-	IsAvailable() bool
+	CheckAvailability() error
+
+	Play(ctx context.Context, streamURL *models.StreamURL, opts Options) (Result, error)
+}
+
+type TerminalPlayer interface {
+	UsesTerminal() bool
+}
 
-	Play(ctx context.Context, streamURL *models.StreamURL, title string) error
+func UsesTerminal(player Player) bool {
+	terminal, ok := player.(TerminalPlayer)
+	return ok && terminal.UsesTerminal()
 }
 
 type Registry struct {
-	players  map[string]Player
-	default_ string
+	players   map[string]Player
+	order     []string
+	preferred string
 }
 
 func NewRegistry() *Registry {
@@ -27,25 +56,66 @@ func NewRegistry() *Registry {
 }
 
 func (r *Registry) Register(name string, player Player) {
+	name = strings.ToLower(name)
+	if _, exists := r.players[name]; !exists {
+		r.order = append(r.order, name)
+	}
 	r.players[name] = player
-	if r.default_ == "" && player.IsAvailable() {
-		r.default_ = name
+}
+
+func (r *Registry) SetPreferred(name string) {
+	r.preferred = strings.ToLower(strings.TrimSpace(name))
+}
+
+func (r *Registry) List() []string {
+	return append([]string(nil), r.order...)
+}
+
+func (r *Registry) Lookup(name string) (Player, bool) {
+	player, exists := r.players[strings.ToLower(name)]
+	return player, exists
+}
+
+func (r *Registry) Get(name string) (Player, error) {
+	player, exists := r.Lookup(name)
+	if !exists {
+		return nil, fmt.Errorf("player '%s' not found (available: %s)", name, strings.Join(r.order, ", "))
+	}
+
+	if err := player.CheckAvailability(); err != nil {
+		return nil, fmt.Errorf("player '%s' is not available: %w", name, err)
 	}
+
+	return player, nil
 }
 
 func (r *Registry) GetDefault() (Player, error) {
-	if r.default_ == "" {
-		return nil, fmt.Errorf("no default player available")
+	var reasons []string
+
+	if r.preferred != "" {
+		player, err := r.Get(r.preferred)
+		if err == nil {
+			return player, nil
+		}
+		log.Debug("Preferred player unavailable, falling back", "player", r.preferred, "error", err)
+		reasons = append(reasons, err.Error())
 	}
 
-	player, exists := r.players[r.default_]
-	if !exists {
-		return nil, fmt.Errorf("player '%s' not found", r.default_)
+	for _, name := range r.order {
+		if name == r.preferred {
+			continue
+		}
+
+		player, err := r.Get(name)
+		if err == nil {
+			return player, nil
+		}
+		reasons = append(reasons, err.Error())
 	}
 
-	if !player.IsAvailable() {
-		return nil, fmt.Errorf("player '%s' is not available", r.default_)
+	if len(reasons) == 0 {
+		return nil, fmt.Errorf("no players registered")
 	}
 
-	return player, nil
+	return nil, fmt.Errorf("%s", strings.Join(reasons, "; "))
 }
@@ -0,0 +1,79 @@
+package players
+
+import (
+	"fmt"
+	"time"
+)
+
+type EndReason int
+
+const (
+	EndEOF EndReason = iota
+	EndQuit
+	EndError
+)
+
+func (r EndReason) String() string {
+	switch r {
+	case EndEOF:
+		return "eof"
+	case EndQuit:
+		return "quit"
+	case EndError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+type Result struct {
+	Reason   EndReason
+	Position time.Duration
+	Duration time.Duration
+}
+
+func (r Result) Percent() float64 {
+	if r.Reason == EndEOF {
+		return 100
+	}
+	if r.Duration <= 0 {
+		return 0
+	}
+
+	percent := float64(r.Position) / float64(r.Duration) * 100
+	return min(max(percent, 0), 100)
+}
+
+func (r Result) String() string {
+	switch r.Reason {
+	case EndEOF:
+		return "Finished episode"
+	case EndError:
+		if r.Duration > 0 {
+			return fmt.Sprintf("Playback failed at %s / %s", FormatPosition(r.Position), FormatPosition(r.Duration))
+		}
+		return "Playback failed"
+	default:
+		if r.Duration > 0 {
+			return fmt.Sprintf("Stopped at %s / %s (%.0f%%)", FormatPosition(r.Position), FormatPosition(r.Duration), r.Percent())
+		}
+		return "Playback stopped"
+	}
+}
+
+func FormatPosition(d time.Duration) string {
+	seconds := int(d.Round(time.Second).Seconds())
+	if seconds < 0 {
+		seconds = 0
+	}
+
+	hours, minutes, seconds := seconds/3600, (seconds/60)%60, seconds%60
+	if hours > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", hours, minutes, seconds)
+	}
+	return fmt.Sprintf("%d:%02d", minutes, seconds)
+}
+
+func Seconds(value float64) time.Duration {
+	return time.Duration(value * float64(time.Second))
+}
@@ -0,0 +1,90 @@
+package players
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Status struct {
+	Position   time.Duration
+	Duration   time.Duration
+	Paused     bool
+	Buffering  bool
+	Recovering bool
+	Subtitle   string
+	Audio      string
+}
+
+func (s Status) Percent() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+
+	percent := float64(s.Position) / float64(s.Duration) * 100
+	return min(max(percent, 0), 100)
+}
+
+func (s Status) String() string {
+	state := "Playing"
+	switch {
+	case s.Recovering:
+		state = "Reconnecting"
+	case s.Buffering:
+		state = "Buffering"
+	case s.Paused:
+		state = "Paused"
+	}
+
+	parts := []string{state}
+	if s.Duration > 0 {
+		parts = append(parts, fmt.Sprintf("%s / %s", FormatPosition(s.Position), FormatPosition(s.Duration)))
+	}
+	if s.Audio != "" {
+		parts = append(parts, "audio "+s.Audio)
+	}
+	if s.Subtitle != "" {
+		parts = append(parts, "subs "+s.Subtitle)
+	}
+	return strings.Join(parts, " · ")
+}
+
+type Command int
+
+const (
+	CommandTogglePause Command = iota
+	CommandSeek
+	CommandSeekTo
+	CommandCycleSubtitle
+	CommandCycleAudio
+)
+
+func (c Command) String() string {
+	switch c {
+	case CommandTogglePause:
+		return "pause"
+	case CommandSeek:
+		return "seek"
+	case CommandSeekTo:
+		return "seek-to"
+	case CommandCycleSubtitle:
+		return "cycle-subtitle"
+	case CommandCycleAudio:
+		return "cycle-audio"
+	default:
+		return "unknown"
+	}
+}
+
+type Control struct {
+	Command Command
+	Offset  time.Duration
+}
+
+func Seek(offset time.Duration) Control {
+	return Control{Command: CommandSeek, Offset: offset}
+}
+
+func SeekTo(position time.Duration) Control {
+	return Control{Command: CommandSeekTo, Offset: position}
+}
@@ -0,0 +1,501 @@
+package vlc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/players"
+	"github.com/hayasedb/hayase-cli/internal/storage"
+)
+
+const (
+	pollInterval   = 500 * time.Millisecond
+	startTimeout   = 30 * time.Second
+	stopTimeout    = 3 * time.Second
+	endThreshold   = 0.98
+	requestTimeout = 2 * time.Second
+)
+
+var binaries = []string{"vlc", "cvlc"}
+
+type Player struct {
+	name   string
+	config *storage.Config
+}
+
+func New(config *storage.Config) players.Player {
+	return &Player{
+		name:   "VLC",
+		config: config,
+	}
+}
+
+func (p *Player) Name() string {
+	return p.name
+}
+
+func (p *Player) CheckAvailability() error {
+	if _, err := p.binary(); err != nil {
+		return fmt.Errorf("no vlc binary found on PATH (tried %s)", strings.Join(p.candidates(), ", "))
+	}
+	return nil
+}
+
+func (p *Player) candidates() []string {
+	if p.config != nil {
+		if path := p.config.GetString("vlc.path"); path != "" {
+			return []string{path}
+		}
+	}
+	return binaries
+}
+
+func (p *Player) binary() (string, error) {
+	var lastErr error
+	for _, candidate := range p.candidates() {
+		path, err := exec.LookPath(candidate)
+		if err == nil {
+			return path, nil
+		}
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+func (p *Player) Play(ctx context.Context, streamURL *models.StreamURL, opts players.Options) (players.Result, error) {
+	failed := players.Result{Reason: players.EndError}
+
+	if streamURL.IsExpired() {
+		return failed, fmt.Errorf("stream URL has expired at %v (current time: %v)",
+			streamURL.ExpiresAt, time.Now())
+	}
+
+	if streamURL.URL == "" {
+		return failed, fmt.Errorf("stream URL is empty")
+	}
+
+	binary, err := p.binary()
+	if err != nil {
+		return failed, fmt.Errorf("vlc binary not found: %w", err)
+	}
+
+	port, err := freePort()
+	if err != nil {
+		return failed, fmt.Errorf("failed to reserve a port for the VLC http interface: %w", err)
+	}
+
+	status := &statusClient{
+		baseURL:  fmt.Sprintf("http://127.0.0.1:%d", port),
+		password: fmt.Sprintf("hayase-%d", time.Now().UnixNano()),
+		client:   &http.Client{Timeout: requestTimeout},
+	}
+
+	args := append(p.arguments(streamURL, opts),
+		"--extraintf=http",
+		"--http-host=127.0.0.1",
+		"--http-port="+strconv.Itoa(port),
+		"--http-password="+status.password,
+		streamURL.URL,
+	)
+
+	cmd := exec.Command(binary, args...)
+	if err := cmd.Start(); err != nil {
+		return failed, fmt.Errorf("failed to start vlc: %w", err)
+	}
+
+	exited := make(chan struct{})
+	var exitErr error
+	go func() {
+		exitErr = cmd.Wait()
+		close(exited)
+	}()
+
+	log.Info("Starting playback", "title", opts.Title, "provider", streamURL.Provider, "quality", streamURL.Quality.String(), "player", p.name)
+
+	result, err := p.watch(ctx, status, streamURL, opts, exited, func() error { return exitErr })
+
+	select {
+	case <-exited:
+	default:
+		if err := status.command(context.Background(), "pl_stop", ""); err != nil {
+			log.Debug("Failed to stop VLC playback", "error", err)
+		}
+		select {
+		case <-exited:
+		case <-time.After(stopTimeout):
+			log.Debug("VLC did not exit, killing process")
+			if killErr := cmd.Process.Kill(); killErr != nil {
+				log.Debug("Failed to kill vlc", "error", killErr)
+			}
+			<-exited
+		}
+	}
+
+	if exitErr != nil {
+		log.Debug("VLC exited", "error", exitErr)
+	}
+
+	return result, err
+}
+
+func (p *Player) arguments(streamURL *models.StreamURL, opts players.Options) []string {
+	userAgent := streamURL.Header("User-Agent")
+	args := []string{
+		"--no-video-title-show",
+		"--quiet",
+	}
+
+	if opts.Refresh == nil {
+		args = append(args, "--play-and-exit")
+	}
+
+	if p.config == nil || p.config.GetBool("vlc.fullscreen") {
+		args = append(args, "--fullscreen")
+	}
+
+	if userAgent != "" {
+		args = append(args, "--http-user-agent="+userAgent)
+	}
+
+	if referrer := streamURL.Header("Referer"); referrer != "" {
+		args = append(args, "--http-referrer="+referrer)
+	}
+
+	if fields := streamURL.HeaderFields("User-Agent", "Referer"); len(fields) > 0 {
+		log.Debug("VLC cannot send custom headers, some may be missing", "headers", fields)
+	}
+
+	if opts.Title != "" {
+		args = append(args, "--meta-title="+opts.Title)
+	}
+
+	if opts.Start > 0 {
+		args = append(args, "--start-time="+strconv.FormatFloat(opts.Start.Seconds(), 'f', 1, 64))
+	}
+
+	if height := bandwidthHeight(streamURL); height > 0 {
+		args = append(args, "--adaptive-maxheight="+strconv.Itoa(height))
+	}
+
+	if opts.Tracks.Audio != "" {
+		args = append(args, "--audio-language="+strings.Join(models.LanguageAliases(opts.Tracks.Audio), ","))
+	}
+
+	switch {
+	case opts.Tracks.SubtitlesOff():
+		args = append(args, "--no-spu")
+	case opts.Tracks.ForcedOnly:
+		log.Debug("VLC cannot select forced subtitles only, leaving subtitles to the stream defaults")
+	case opts.Tracks.Subtitle != "":
+		args = append(args, "--sub-language="+strings.Join(models.LanguageAliases(opts.Tracks.Subtitle), ","))
+	}
+
+	return args
+}
+
+func (p *Player) watch(ctx context.Context, status *statusClient, streamURL *models.StreamURL, opts players.Options, exited <-chan struct{}, exitError func() error) (players.Result, error) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	started := false
+	startDeadline := time.After(startTimeout)
+	var last playbackStatus
+	var reported players.Status
+	reloading := false
+
+	watchdog := players.NewWatchdog(players.DefaultStallTimeout)
+	recovery := players.NewRecovery(opts.Refresh)
+
+	result := func(reason players.EndReason) players.Result {
+		return players.Result{
+			Reason:   reason,
+			Position: time.Duration(last.Time) * time.Second,
+			Duration: time.Duration(last.Length) * time.Second,
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			log.Debug("Playback cancelled by context")
+			return result(players.EndQuit), nil
+
+		case <-exited:
+			switch {
+			case !started:
+				log.Error("Playback error", "error", "vlc exited before playback started")
+				return result(players.EndError), fmt.Errorf("playback error: vlc exited before playback started")
+			case last.Length > 0 && last.Position >= endThreshold:
+				log.Debug("Playback finished normally", "time", last.Time, "length", last.Length)
+				return result(players.EndEOF), nil
+			case exitError() != nil:
+				log.Error("Playback error", "error", exitError())
+				return result(players.EndError), fmt.Errorf("playback error: vlc exited unexpectedly: %w", exitError())
+			default:
+				log.Debug("User quit playback", "time", last.Time, "length", last.Length)
+				return result(players.EndQuit), nil
+			}
+
+		case control := <-opts.Controls:
+			command, value := controlCommand(control)
+			if command == "" {
+				continue
+			}
+			if err := status.command(ctx, command, value); err != nil {
+				log.Debug("Failed to send control to VLC", "control", control.Command.String(), "error", err)
+			}
+
+		case refreshed := <-recovery.Results():
+			recovery.Finish()
+			if refreshed.Err != nil {
+				log.Error("Failed to recover playback", "error", refreshed.Err)
+				return result(players.EndError), fmt.Errorf("stream failed (%s) and could not be extracted again: %w", recovery.Reason, refreshed.Err)
+			}
+
+			streamURL = refreshed.Stream
+			log.Info("Reloading stream", "provider", streamURL.Provider, "quality", streamURL.Quality.String(), "position", players.FormatPosition(recovery.Position))
+			if err := status.play(ctx, streamURL.URL, itemOptions(streamURL, recovery.Position)); err != nil {
+				return result(players.EndError), fmt.Errorf("failed to reload stream: %w", err)
+			}
+			reloading = true
+			watchdog.Reset()
+
+			reported.Recovering = false
+			opts.Notify(reported)
+
+		case <-startDeadline:
+			if !started {
+				return result(players.EndError), fmt.Errorf("playback error: vlc did not start playing within %s", startTimeout)
+			}
+
+		case <-ticker.C:
+			current, err := status.status(ctx)
+			if err != nil {
+				log.Debug("Failed to read VLC status", "error", err)
+				continue
+			}
+
+			if current.State != last.State {
+				log.Debug("VLC state changed", "state", current.State, "time", current.Time, "length", current.Length)
+			}
+
+			if !started && current.State == "playing" {
+				started = true
+				log.Debug("Playback started")
+				p.loadSubtitles(ctx, status, streamURL, opts.Tracks)
+			}
+
+			if reloading && current.State == "playing" {
+				reloading = false
+				p.loadSubtitles(ctx, status, streamURL, opts.Tracks)
+			}
+
+			if started && current.State == "stopped" {
+				switch {
+				case recovery.Active() || reloading:
+				case last.Length > 0 && last.Position >= endThreshold:
+					log.Debug("Playback finished normally")
+					return result(players.EndEOF), nil
+				case recovery.Start(ctx, "playback stopped at "+players.FormatPosition(time.Duration(last.Time)*time.Second), time.Duration(last.Time)*time.Second):
+					log.Warn("Playback stopped early, extracting the stream again", "position", players.FormatPosition(time.Duration(last.Time)*time.Second))
+				default:
+					log.Debug("Playback stopped", "time", last.Time, "length", last.Length)
+					return result(players.EndQuit), nil
+				}
+				continue
+			}
+
+			last = current
+
+			if started {
+				update := players.Status{
+					Position:  time.Duration(last.Time) * time.Second,
+					Duration:  time.Duration(last.Length) * time.Second,
+					Paused:    last.State == "paused",
+					Buffering: last.State == "buffering",
+				}
+				update.Recovering = recovery.Active()
+				if update != reported {
+					reported = update
+					opts.Notify(update)
+				}
+
+				watchdog.Observe(update)
+				if reason, stalled := watchdog.Check(); stalled && !recovery.Active() {
+					if recovery.Start(ctx, reason, update.Position) {
+						log.Warn("Playback interrupted, extracting the stream again", "reason", reason, "position", players.FormatPosition(update.Position))
+					} else {
+						watchdog.Reset()
+					}
+				}
+			}
+		}
+	}
+}
+
+func controlCommand(control players.Control) (string, string) {
+	switch control.Command {
+	case players.CommandTogglePause:
+		return "pl_pause", ""
+	case players.CommandSeek:
+		return "seek", fmt.Sprintf("%+d", int(control.Offset.Seconds()))
+	case players.CommandSeekTo:
+		return "seek", strconv.Itoa(int(control.Offset.Seconds()))
+	case players.CommandCycleSubtitle:
+		return "key", "subtitle-track"
+	case players.CommandCycleAudio:
+		return "key", "audio-track"
+	default:
+		return "", ""
+	}
+}
+
+func itemOptions(streamURL *models.StreamURL, position time.Duration) []string {
+	var options []string
+	if userAgent := streamURL.Header("User-Agent"); userAgent != "" {
+		options = append(options, ":http-user-agent="+userAgent)
+	}
+	if referrer := streamURL.Header("Referer"); referrer != "" {
+		options = append(options, ":http-referrer="+referrer)
+	}
+	if position > 0 {
+		options = append(options, ":start-time="+strconv.FormatFloat(position.Seconds(), 'f', 1, 64))
+	}
+	return options
+}
+
+func (p *Player) loadSubtitles(ctx context.Context, status *statusClient, streamURL *models.StreamURL, tracks models.TrackPreference) {
+	preferred, hasPreferred := streamURL.PreferredSubtitle(tracks)
+
+	var ordered []models.Subtitle
+	for _, sub := range streamURL.Subtitles {
+		if sub.URL == "" || sub.Embedded || (hasPreferred && sub.URL == preferred.URL) {
+			continue
+		}
+		ordered = append(ordered, sub)
+	}
+	if hasPreferred && !preferred.Embedded {
+		ordered = append(ordered, preferred)
+	}
+
+	for _, sub := range ordered {
+		if err := status.command(ctx, "addsubtitle", sub.URL); err != nil {
+			log.Debug("Failed to add subtitle track", "url", sub.URL, "error", err)
+		}
+	}
+}
+
+type playbackStatus struct {
+	State    string  `json:"state"`
+	Time     int     `json:"time"`
+	Length   int     `json:"length"`
+	Position float64 `json:"position"`
+}
+
+type statusClient struct {
+	baseURL  string
+	password string
+	client   *http.Client
+}
+
+func (s *statusClient) status(ctx context.Context) (playbackStatus, error) {
+	var status playbackStatus
+
+	resp, err := s.request(ctx, url.Values{})
+	if err != nil {
+		return status, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			log.Debug("Failed to close response body", "error", err)
+		}
+	}()
+
+	if err := json.NewDecoder(resp.Body).Decode(&status); err != nil {
+		return status, fmt.Errorf("failed to decode VLC status: %w", err)
+	}
+	return status, nil
+}
+
+func (s *statusClient) command(ctx context.Context, command, value string) error {
+	query := url.Values{"command": {command}}
+	if value != "" {
+		query.Set("val", value)
+	}
+
+	resp, err := s.request(ctx, query)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *statusClient) play(ctx context.Context, input string, options []string) error {
+	resp, err := s.request(ctx, url.Values{"command": {"in_play"}, "input": {input}, "option": options})
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+func (s *statusClient) request(ctx context.Context, query url.Values) (*http.Response, error) {
+	target := s.baseURL + "/requests/status.json"
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth("", s.password)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("VLC http interface returned status %d", resp.StatusCode)
+	}
+	return resp, nil
+}
+
+func bandwidthHeight(streamURL *models.StreamURL) int {
+	if streamURL.Bandwidth <= 0 {
+		return 0
+	}
+	for _, v := range streamURL.Variants {
+		if v.Bandwidth == streamURL.Bandwidth {
+			return v.Height
+		}
+	}
+	return 0
+}
+
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := listener.Close(); err != nil && !errors.Is(err, net.ErrClosed) {
+			log.Debug("Failed to release port", "error", err)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
@@ -0,0 +1,125 @@
+package players
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+const (
+	DefaultStallTimeout = 20 * time.Second
+	MaxRecoveries       = 3
+)
+
+type RefreshFunc func(ctx context.Context) (*models.StreamURL, error)
+
+type Watchdog struct {
+	timeout    time.Duration
+	armed      bool
+	paused     bool
+	position   time.Duration
+	progressed time.Time
+	failure    string
+	now        func() time.Time
+}
+
+func NewWatchdog(timeout time.Duration) *Watchdog {
+	if timeout <= 0 {
+		timeout = DefaultStallTimeout
+	}
+	return &Watchdog{timeout: timeout, now: time.Now}
+}
+
+func (w *Watchdog) Observe(status Status) {
+	if status.Position <= 0 && status.Duration <= 0 {
+		return
+	}
+
+	paused := status.Paused && !status.Buffering
+	if !w.armed || status.Position != w.position || paused != w.paused {
+		w.progressed = w.now()
+	}
+	w.armed = true
+	w.paused = paused
+	w.position = status.Position
+}
+
+func (w *Watchdog) Fail(reason string) {
+	if w.failure == "" {
+		w.failure = reason
+	}
+}
+
+func (w *Watchdog) Failure() string {
+	return w.failure
+}
+
+func (w *Watchdog) Check() (string, bool) {
+	if w.failure != "" {
+		return w.failure, true
+	}
+
+	if !w.armed || w.paused {
+		return "", false
+	}
+
+	if stalled := w.now().Sub(w.progressed); stalled >= w.timeout {
+		return fmt.Sprintf("no progress for %s at %s", stalled.Round(time.Second), FormatPosition(w.position)), true
+	}
+	return "", false
+}
+
+func (w *Watchdog) Reset() {
+	w.armed = false
+	w.failure = ""
+	w.progressed = w.now()
+}
+
+type RefreshResult struct {
+	Stream *models.StreamURL
+	Err    error
+}
+
+type Recovery struct {
+	refresh  RefreshFunc
+	attempts int
+	pending  chan RefreshResult
+	Reason   string
+	Position time.Duration
+}
+
+func NewRecovery(refresh RefreshFunc) *Recovery {
+	return &Recovery{refresh: refresh}
+}
+
+func (r *Recovery) Start(ctx context.Context, reason string, position time.Duration) bool {
+	if r.refresh == nil || r.pending != nil || r.attempts >= MaxRecoveries {
+		return false
+	}
+
+	r.attempts++
+	r.Reason = reason
+	r.Position = position
+	r.pending = make(chan RefreshResult, 1)
+
+	pending := r.pending
+	go func() {
+		stream, err := r.refresh(ctx)
+		pending <- RefreshResult{Stream: stream, Err: err}
+	}()
+	return true
+}
+
+func (r *Recovery) Active() bool {
+	return r.pending != nil
+}
+
+func (r *Recovery) Results() <-chan RefreshResult {
+	return r.pending
+}
+
+func (r *Recovery) Finish() {
+	r.pending = nil
+}
@@ -0,0 +1,163 @@
+package players
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+const stallTimeout = 20 * time.Second
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func (c *clock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestWatchdog() (*Watchdog, *clock) {
+	c := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	watchdog := NewWatchdog(stallTimeout)
+	watchdog.now = c.Now
+	return watchdog, c
+}
+
+func TestWatchdog(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  Status
+		stalled bool
+	}{
+		{"playing without progress", Status{Position: time.Minute, Duration: time.Hour}, true},
+		{"paused", Status{Position: time.Minute, Duration: time.Hour, Paused: true}, false},
+		{"buffering while paused", Status{Position: time.Minute, Duration: time.Hour, Paused: true, Buffering: true}, true},
+		{"nothing loaded yet", Status{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			watchdog, clock := newTestWatchdog()
+			watchdog.Observe(tt.status)
+
+			clock.Advance(stallTimeout - time.Second)
+			watchdog.Observe(tt.status)
+			if _, stalled := watchdog.Check(); stalled {
+				t.Fatal("stall reported before the timeout")
+			}
+
+			clock.Advance(time.Second)
+			watchdog.Observe(tt.status)
+			if _, stalled := watchdog.Check(); stalled != tt.stalled {
+				t.Errorf("Check stalled = %v, want %v", stalled, tt.stalled)
+			}
+		})
+	}
+}
+
+func TestWatchdogProgress(t *testing.T) {
+	watchdog, clock := newTestWatchdog()
+	position := time.Minute
+	for range 4 {
+		watchdog.Observe(Status{Position: position, Duration: time.Hour})
+		clock.Advance(stallTimeout / 2)
+		position += time.Second
+	}
+	if reason, stalled := watchdog.Check(); stalled {
+		t.Errorf("advancing playback reported as stalled: %s", reason)
+	}
+}
+
+func TestWatchdogResume(t *testing.T) {
+	watchdog, clock := newTestWatchdog()
+	status := Status{Position: time.Minute, Duration: time.Hour, Paused: true}
+	watchdog.Observe(status)
+	clock.Advance(time.Hour)
+
+	status.Paused = false
+	watchdog.Observe(status)
+	if _, stalled := watchdog.Check(); stalled {
+		t.Error("time spent paused counted towards a stall")
+	}
+}
+
+func TestWatchdogFailure(t *testing.T) {
+	watchdog, _ := newTestWatchdog()
+	watchdog.Fail("stream error")
+	watchdog.Fail("second error")
+
+	if reason, stalled := watchdog.Check(); !stalled || reason != "stream error" {
+		t.Errorf("Check = %q, %v, want the first failure", reason, stalled)
+	}
+
+	watchdog.Reset()
+	if watchdog.Failure() != "" {
+		t.Errorf("Failure after Reset = %q, want empty", watchdog.Failure())
+	}
+	if _, stalled := watchdog.Check(); stalled {
+		t.Error("Check reported a stall after Reset")
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	stream := &models.StreamURL{URL: "https://cdn.example/refreshed.m3u8"}
+	recovery := NewRecovery(func(context.Context) (*models.StreamURL, error) {
+		return stream, nil
+	})
+	ctx := context.Background()
+
+	for attempt := 1; attempt <= MaxRecoveries; attempt++ {
+		if !recovery.Start(ctx, "stalled", time.Minute) {
+			t.Fatalf("attempt %d refused", attempt)
+		}
+		if recovery.Start(ctx, "stalled", time.Minute) {
+			t.Fatalf("attempt %d started while another recovery was pending", attempt)
+		}
+		if !recovery.Active() {
+			t.Fatal("recovery not active after Start")
+		}
+
+		select {
+		case result := <-recovery.Results():
+			if result.Err != nil || result.Stream != stream {
+				t.Fatalf("unexpected result %+v", result)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("no refresh result")
+		}
+		recovery.Finish()
+	}
+
+	if recovery.Start(ctx, "stalled", time.Minute) {
+		t.Errorf("recovery started beyond the limit of %d", MaxRecoveries)
+	}
+	if recovery.Reason != "stalled" || recovery.Position != time.Minute {
+		t.Errorf("recovery kept reason %q at %s", recovery.Reason, recovery.Position)
+	}
+}
+
+func TestRecoveryError(t *testing.T) {
+	errRefresh := errors.New("no hoster left")
+	recovery := NewRecovery(func(context.Context) (*models.StreamURL, error) {
+		return nil, errRefresh
+	})
+	if !recovery.Start(context.Background(), "stalled", 0) {
+		t.Fatal("recovery refused")
+	}
+	if result := <-recovery.Results(); !errors.Is(result.Err, errRefresh) {
+		t.Errorf("result error = %v, want %v", result.Err, errRefresh)
+	}
+}
+
+func TestRecoveryWithoutRefresh(t *testing.T) {
+	if NewRecovery(nil).Start(context.Background(), "stalled", 0) {
+		t.Error("recovery started without a refresh function")
+	}
+}
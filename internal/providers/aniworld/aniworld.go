@@ -12,6 +12,7 @@ import (
 
 	"github.com/charmbracelet/log"
 
+	"github.com/hayasedb/hayase-cli/internal/extractors"
 	"github.com/hayasedb/hayase-cli/internal/models"
 	"github.com/hayasedb/hayase-cli/internal/providers"
 )
@@ -20,9 +21,9 @@ type Provider struct {
 	client *Client
 }
 
-func New() providers.Provider {
+func New(extractorSystem *extractors.System) providers.Provider {
 	return &Provider{
-		client: NewClient(),
+		client: NewClient(extractorSystem),
 	}
 }
 
@@ -91,6 +92,11 @@ func (p *Provider) GetEpisodes(ctx context.Context, anime *models.Anime) error {
 	}
 	log.Debug("Anime page fetched successfully", "slug", anime.Slug)
 
+	if malID := p.client.ParseMALID(doc); malID > 0 {
+		anime.MALID = malID
+		log.Debug("Found MyAnimeList id", "slug", anime.Slug, "mal_id", malID)
+	}
+
 	log.Debug("Parsing available seasons", "slug", anime.Slug)
 	availableSeasons := p.client.ParseAvailableSeasons(doc)
 	log.Debug("Available seasons parsed", "slug", anime.Slug, "seasons", availableSeasons)
@@ -26,16 +26,21 @@ type Client struct {
 	httpClient *http.Client
 	userAgent  string
 	timeout    time.Duration
+	extractors *extractors.System
 }
 
-func NewClient() *Client {
+func NewClient(extractorSystem *extractors.System) *Client {
 	timeout := 10 * time.Second
+	if extractorSystem == nil {
+		extractorSystem = extractors.NewSystem(nil)
+	}
 	return &Client{
 		httpClient: &http.Client{
 			Timeout: timeout,
 		},
-		userAgent: "Mozilla/5.0 (X11; Linux x86_64; rv:98.0) Gecko/20100101 Firefox/98.0",
-		timeout:   timeout,
+		userAgent:  "Mozilla/5.0 (X11; Linux x86_64; rv:98.0) Gecko/20100101 Firefox/98.0",
+		timeout:    timeout,
+		extractors: extractorSystem,
 	}
 }
 
@@ -168,15 +173,12 @@ func (c *Client) FollowRedirect(ctx context.Context, redirectURL string) (string
 
 	req.Header.Set("User-Agent", c.userAgent)
 
-	originalCheckRedirect := c.httpClient.CheckRedirect
-	c.httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+	noRedirectClient := *c.httpClient
+	noRedirectClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
 		return http.ErrUseLastResponse
 	}
-	defer func() {
-		c.httpClient.CheckRedirect = originalCheckRedirect
-	}()
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := noRedirectClient.Do(req)
 	if err != nil {
 		return "", fmt.Errorf("request failed: %w", err)
 	}
@@ -196,6 +198,26 @@ func (c *Client) FollowRedirect(ctx context.Context, redirectURL string) (string
 	return redirectURL, nil
 }
 
+func (c *Client) ParseMALID(doc *goquery.Document) int {
+	malID := 0
+	doc.Find("a[href*='myanimelist.net/anime/']").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		href, _ := s.Attr("href")
+		_, rest, found := strings.Cut(href, "myanimelist.net/anime/")
+		if !found {
+			return true
+		}
+		if end := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+			rest = rest[:end]
+		}
+		if n, err := strconv.Atoi(rest); err == nil && n > 0 {
+			malID = n
+			return false
+		}
+		return true
+	})
+	return malID
+}
+
 func (c *Client) ParseAvailableSeasons(doc *goquery.Document) []int {
 	var seasons []int
 	seasonSet := make(map[int]bool)
@@ -303,16 +325,7 @@ func parseMovieURL(url string) (movieNum int, ok bool) {
 }
 
 func (c *Client) ExtractStreamURL(ctx context.Context, redirectURL string) (*models.StreamURL, error) {
-	extractorSystem := extractors.NewSystem(nil)
-
-	embedURL, err := c.FollowRedirect(ctx, redirectURL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to follow redirect: %w", err)
-	}
-
-	log.Debug("Following redirect", "redirect_url", redirectURL, "embed_url", embedURL)
-
-	streamURL, err := extractorSystem.Extract(ctx, embedURL)
+	streamURL, err := c.extractors.ResolveURL(ctx, redirectURL, c.FollowRedirect)
 	if err != nil {
 		return nil, fmt.Errorf("failed to extract stream URL: %w", err)
 	}
@@ -321,3 +334,7 @@ func (c *Client) ExtractStreamURL(ctx context.Context, redirectURL string) (*mod
 
 	return streamURL, nil
 }
+
+func (c *Client) Extractors() *extractors.System {
+	return c.extractors
+}
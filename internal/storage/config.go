@@ -2,8 +2,12 @@ package storage
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 
@@ -51,12 +55,38 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("provider", "aniworld")
 	v.SetDefault("language", "ger-sub")
 	v.SetDefault("quality", "1080p")
+	v.SetDefault("subtitle_language", "")
+	v.SetDefault("subtitle_forced", false)
+	v.SetDefault("audio_language", "")
 
 	v.SetDefault("player", "mpv")
+	v.SetDefault("mpv.mode", "auto")
+	v.SetDefault("mpv.path", "mpv")
+	v.SetDefault("mpv.profile", "")
+	v.SetDefault("mpv.user_config", false)
+	v.SetDefault("mpv.config_dir", "")
+	v.SetDefault("mpv.vo", "auto")
+	v.SetDefault("mpv.terminal_vo", "auto")
+	v.SetDefault("vlc.path", "")
+	v.SetDefault("vlc.fullscreen", true)
+
+	v.SetDefault("binge", false)
+	v.SetDefault("binge_countdown", 5)
+	v.SetDefault("prefetch", true)
+
+	v.SetDefault("aniskip.mode", "off")
+	v.SetDefault("aniskip.url", "https://api.aniskip.com")
 
 	v.SetDefault("instantSearch", true)
 
 	v.SetDefault("timeout", 10)
+
+	v.SetDefault("validate", true)
+	v.SetDefault("cache", true)
+
+	v.SetDefault("race", false)
+	v.SetDefault("race_candidates", 3)
+	v.SetDefault("race_grace_ms", 300)
 }
 
 func getConfigDir() (string, error) {
@@ -106,6 +136,82 @@ func (c *Config) GetPlayer() string {
 	return c.GetString("player")
 }
 
+func (c *Config) GetBinge() bool {
+	return c.GetBool("binge")
+}
+
+func (c *Config) GetPrefetch() bool {
+	return c.GetBool("prefetch")
+}
+
+func (c *Config) GetSkipMode() string {
+	switch mode := strings.ToLower(c.GetString("aniskip.mode")); mode {
+	case "auto", "prompt":
+		return mode
+	default:
+		return "off"
+	}
+}
+
+func (c *Config) GetBingeCountdown() time.Duration {
+	seconds := c.GetInt("binge_countdown")
+	if seconds < 0 {
+		seconds = 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func (c *Config) GetMPVProfile() string {
+	return strings.ToLower(strings.TrimSpace(c.GetString("mpv.profile")))
+}
+
+func (c *Config) GetMPVProfiles() []string {
+	var names []string
+	for name := range c.v.GetStringMap("mpv.profiles") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Config) GetMPVOptions() map[string]string {
+	options := optionMap(c.v.GetStringMap("mpv.options"))
+	if profile := c.GetMPVProfile(); profile != "" {
+		for name, value := range optionMap(c.v.GetStringMap("mpv.profiles." + profile)) {
+			options[name] = value
+		}
+	}
+	return options
+}
+
+func optionMap(values map[string]interface{}) map[string]string {
+	options := make(map[string]string, len(values))
+	for name, value := range values {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case string:
+			switch strings.ToLower(v) {
+			case "true":
+				options[name] = "yes"
+			case "false":
+				options[name] = "no"
+			default:
+				options[name] = v
+			}
+		case bool:
+			if v {
+				options[name] = "yes"
+			} else {
+				options[name] = "no"
+			}
+		default:
+			options[name] = fmt.Sprint(v)
+		}
+	}
+	return options
+}
+
 func (c *Config) GetLanguage() models.Language {
 	lang := c.GetString("language")
 	if parsed, err := models.ParseLanguage(lang); err == nil {
@@ -114,20 +220,95 @@ func (c *Config) GetLanguage() models.Language {
 	return models.GerSub
 }
 
+func (c *Config) GetLanguagePriority() []models.Language {
+	preferred := c.GetLanguage()
+	priority := []models.Language{preferred}
+	seen := map[models.Language]bool{preferred: true}
+
+	for _, value := range c.v.GetStringSlice("languages") {
+		for _, part := range strings.Split(value, ",") {
+			lang, err := models.ParseLanguage(part)
+			if err != nil || seen[lang] {
+				continue
+			}
+			seen[lang] = true
+			priority = append(priority, lang)
+		}
+	}
+
+	for _, lang := range models.AllLanguages() {
+		if !seen[lang] {
+			seen[lang] = true
+			priority = append(priority, lang)
+		}
+	}
+
+	return priority
+}
+
+func (c *Config) GetSubtitleLanguage() string {
+	lang := strings.ToLower(strings.TrimSpace(c.GetString("subtitle_language")))
+	if lang == "off" {
+		return lang
+	}
+	if lang != "" && lang != "auto" {
+		return models.NormalizeLanguageCode(lang)
+	}
+
+	switch c.GetLanguage() {
+	case models.GerSub:
+		return "de"
+	case models.EngSub:
+		return "en"
+	default:
+		return ""
+	}
+}
+
+func (c *Config) GetAudioLanguage() string {
+	lang := strings.ToLower(strings.TrimSpace(c.GetString("audio_language")))
+	if lang != "" && lang != "auto" {
+		return models.NormalizeLanguageCode(lang)
+	}
+
+	if c.GetLanguage() == models.GerDub {
+		return "de"
+	}
+	return "ja"
+}
+
+func (c *Config) GetTrackPreference(anime *models.Anime) models.TrackPreference {
+	tracks := models.TrackPreference{
+		Audio:      c.GetAudioLanguage(),
+		Subtitle:   c.GetSubtitleLanguage(),
+		ForcedOnly: c.GetBool("subtitle_forced"),
+	}
+	if anime == nil || anime.Slug == "" {
+		return tracks
+	}
+
+	prefix := "anime." + strings.ToLower(anime.Slug) + "."
+	if lang := strings.ToLower(strings.TrimSpace(c.GetString(prefix + "audio_language"))); lang != "" {
+		tracks.Audio = models.NormalizeLanguageCode(lang)
+	}
+	if lang := strings.ToLower(strings.TrimSpace(c.GetString(prefix + "subtitle_language"))); lang != "" {
+		if lang != "off" {
+			lang = models.NormalizeLanguageCode(lang)
+		}
+		tracks.Subtitle = lang
+	}
+	if c.GetString(prefix+"subtitle_forced") != "" {
+		tracks.ForcedOnly = c.GetBool(prefix + "subtitle_forced")
+	}
+	return tracks
+}
+
 func (c *Config) GetQuality() models.Quality {
 	quality := c.GetString("quality")
-	switch quality {
-	case "720p":
-		return models.Quality720p
-	case "1080p":
-		return models.Quality1080p
-	case "1440p":
-		return models.Quality1440p
-	case "2160p":
-		return models.Quality2160p
-	default:
-		return models.Quality1080p
+	if parsed, err := models.ParseQuality(quality); err == nil {
+		return parsed
 	}
+	return models.Quality1080p
 }
 
 func (c *Config) GetTimeout() int {
@@ -0,0 +1,156 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	healthFileName  = "hosters.json"
+	healthSmoothing = 0.3
+)
+
+type HosterHealth struct {
+	Successes      int            `json:"successes"`
+	Failures       int            `json:"failures"`
+	SuccessRate    float64        `json:"success_rate"`
+	LatencyMs      float64        `json:"latency_ms"`
+	LastSuccess    time.Time      `json:"last_success,omitempty"`
+	LastFailure    time.Time      `json:"last_failure,omitempty"`
+	LastReason     string         `json:"last_reason,omitempty"`
+	FailureReasons map[string]int `json:"failure_reasons,omitempty"`
+}
+
+func (h HosterHealth) Attempts() int {
+	return h.Successes + h.Failures
+}
+
+type HealthStore struct {
+	mu      sync.Mutex
+	saveMu  sync.Mutex
+	path    string
+	hosters map[string]*HosterHealth
+}
+
+func NewHealthStore() (*HealthStore, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	store := &HealthStore{
+		path:    filepath.Join(configDir, healthFileName),
+		hosters: make(map[string]*HosterHealth),
+	}
+
+	if err := store.load(); err != nil {
+		store.hosters = make(map[string]*HosterHealth)
+		return store, err
+	}
+
+	return store, nil
+}
+
+func (s *HealthStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	return json.Unmarshal(data, &s.hosters)
+}
+
+func (s *HealthStore) Save() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.hosters, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.path, data, 0644)
+}
+
+func (s *HealthStore) RecordSuccess(hoster string, latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.entry(hoster)
+	h.Successes++
+	h.LastSuccess = time.Now()
+	h.SuccessRate = smooth(h.SuccessRate, 1, h.Attempts())
+	h.LatencyMs = smooth(h.LatencyMs, float64(latency.Milliseconds()), h.Successes)
+}
+
+func (s *HealthStore) RecordFailure(hoster, reason string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h := s.entry(hoster)
+	h.Failures++
+	h.LastFailure = time.Now()
+	h.LastReason = reason
+	h.SuccessRate = smooth(h.SuccessRate, 0, h.Attempts())
+
+	if h.FailureReasons == nil {
+		h.FailureReasons = make(map[string]int)
+	}
+	h.FailureReasons[reason]++
+}
+
+func (s *HealthStore) Get(hoster string) (HosterHealth, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	h, exists := s.hosters[hoster]
+	if !exists {
+		return HosterHealth{}, false
+	}
+	return *h, true
+}
+
+func (s *HealthStore) Hosters() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.hosters))
+	for name := range s.hosters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *HealthStore) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.hosters = make(map[string]*HosterHealth)
+}
+
+func (s *HealthStore) entry(hoster string) *HosterHealth {
+	h, exists := s.hosters[hoster]
+	if !exists {
+		h = &HosterHealth{}
+		s.hosters[hoster] = h
+	}
+	return h
+}
+
+func smooth(current, sample float64, count int) float64 {
+	if count <= 1 {
+		return sample
+	}
+	return current + healthSmoothing*(sample-current)
+}
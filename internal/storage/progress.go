@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+const (
+	progressFileName = "progress.json"
+
+	minResumePosition = 30 * time.Second
+	finishedThreshold = 0.95
+)
+
+type EpisodeProgress struct {
+	Position  time.Duration `json:"position"`
+	Duration  time.Duration `json:"duration"`
+	Finished  bool          `json:"finished"`
+	UpdatedAt time.Time     `json:"updated_at"`
+}
+
+func (p EpisodeProgress) Resumable() bool {
+	if p.Finished || p.Position < minResumePosition {
+		return false
+	}
+	if p.Duration > 0 && float64(p.Position) >= float64(p.Duration)*finishedThreshold {
+		return false
+	}
+	return true
+}
+
+type ProgressStore struct {
+	mu       sync.Mutex
+	saveMu   sync.Mutex
+	path     string
+	episodes map[string]*EpisodeProgress
+}
+
+func NewProgressStore() (*ProgressStore, error) {
+	configDir, err := getConfigDir()
+	if err != nil {
+		return nil, err
+	}
+
+	store := &ProgressStore{
+		path:     filepath.Join(configDir, progressFileName),
+		episodes: make(map[string]*EpisodeProgress),
+	}
+
+	if err := store.load(); err != nil {
+		store.episodes = make(map[string]*EpisodeProgress)
+		return store, err
+	}
+
+	return store, nil
+}
+
+func progressKey(anime *models.Anime, episode *models.Episode) string {
+	id := anime.Slug
+	if id == "" {
+		id = anime.Title
+	}
+	return fmt.Sprintf("%s/s%02de%02d", id, episode.Season, episode.Episode)
+}
+
+func (s *ProgressStore) load() error {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	return json.Unmarshal(data, &s.episodes)
+}
+
+func (s *ProgressStore) Save() error {
+	s.saveMu.Lock()
+	defer s.saveMu.Unlock()
+
+	s.mu.Lock()
+	data, err := json.MarshalIndent(s.episodes, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(s.path, data, 0644)
+}
+
+func (s *ProgressStore) Get(anime *models.Anime, episode *models.Episode) (EpisodeProgress, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, exists := s.episodes[progressKey(anime, episode)]
+	if !exists {
+		return EpisodeProgress{}, false
+	}
+	return *p, true
+}
+
+func (s *ProgressStore) Record(anime *models.Anime, episode *models.Episode, position, duration time.Duration, finished bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if duration > 0 && float64(position) >= float64(duration)*finishedThreshold {
+		finished = true
+	}
+	if finished {
+		position = 0
+	}
+
+	s.episodes[progressKey(anime, episode)] = &EpisodeProgress{
+		Position:  position,
+		Duration:  duration,
+		Finished:  finished,
+		UpdatedAt: time.Now(),
+	}
+}
+
+func (s *ProgressStore) Resume(anime *models.Anime, episode *models.Episode) (time.Duration, bool) {
+	p, exists := s.Get(anime, episode)
+	if !exists || !p.Resumable() {
+		return 0, false
+	}
+	return p.Position, true
+}
+
+func (s *ProgressStore) Clear(anime *models.Anime, episode *models.Episode) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.episodes, progressKey(anime, episode))
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/hayasedb/hayase-cli/internal/models"
+)
+
+const (
+	streamCacheFileName = "streams.json"
+	RedirectCacheTTL    = 24 * time.Hour
+)
+
+type redirectEntry struct {
+	EmbedURL  string    `json:"embed_url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type streamCacheData struct {
+	Redirects map[string]redirectEntry     `json:"redirects"`
+	Streams   map[string]*models.StreamURL `json:"streams"`
+}
+
+type StreamCache struct {
+	mu     sync.Mutex
+	saveMu sync.Mutex
+	path   string
+	data   streamCacheData
+}
+
+func NewStreamCache() (*StreamCache, error) {
+	cacheDir, err := getCacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	cache := &StreamCache{
+		path: filepath.Join(cacheDir, streamCacheFileName),
+	}
+	cache.reset()
+
+	if err := cache.load(); err != nil {
+		cache.reset()
+		return cache, err
+	}
+
+	return cache, nil
+}
+
+func getCacheDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "hayase-cli"), nil
+}
+
+func (c *StreamCache) reset() {
+	c.data = streamCacheData{
+		Redirects: make(map[string]redirectEntry),
+		Streams:   make(map[string]*models.StreamURL),
+	}
+}
+
+func (c *StreamCache) load() error {
+	raw, err := os.ReadFile(c.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	if err := json.Unmarshal(raw, &c.data); err != nil {
+		return err
+	}
+
+	if c.data.Redirects == nil {
+		c.data.Redirects = make(map[string]redirectEntry)
+	}
+	if c.data.Streams == nil {
+		c.data.Streams = make(map[string]*models.StreamURL)
+	}
+
+	c.prune()
+	return nil
+}
+
+func (c *StreamCache) Save() error {
+	c.saveMu.Lock()
+	defer c.saveMu.Unlock()
+
+	c.mu.Lock()
+	c.prune()
+	raw, err := json.Marshal(c.data)
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(c.path, raw, 0600)
+}
+
+func (c *StreamCache) GetEmbed(redirectURL string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, exists := c.data.Redirects[redirectURL]
+	if !exists || time.Now().After(entry.ExpiresAt) {
+		return "", false
+	}
+	return entry.EmbedURL, true
+}
+
+func (c *StreamCache) PutEmbed(redirectURL, embedURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data.Redirects[redirectURL] = redirectEntry{
+		EmbedURL:  embedURL,
+		ExpiresAt: time.Now().Add(RedirectCacheTTL),
+	}
+}
+
+func (c *StreamCache) GetStream(embedURL string, minRemaining time.Duration) (*models.StreamURL, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stream, exists := c.data.Streams[embedURL]
+	if !exists || stream.ExpiresWithin(minRemaining) {
+		return nil, false
+	}
+
+	return stream.Clone(), true
+}
+
+func (c *StreamCache) PutStream(embedURL string, stream *models.StreamURL) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data.Streams[embedURL] = stream.Clone()
+}
+
+func (c *StreamCache) Invalidate(embedURL string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.data.Streams, embedURL)
+}
+
+func (c *StreamCache) prune() {
+	now := time.Now()
+	for key, entry := range c.data.Redirects {
+		if now.After(entry.ExpiresAt) {
+			delete(c.data.Redirects, key)
+		}
+	}
+	for key, stream := range c.data.Streams {
+		if stream.IsExpired() {
+			delete(c.data.Streams, key)
+		}
+	}
+}
@@ -2,11 +2,15 @@ package app
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 
+	"github.com/hayasedb/hayase-cli/internal/aniskip"
 	"github.com/hayasedb/hayase-cli/internal/extractors"
 	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/playback"
 	"github.com/hayasedb/hayase-cli/internal/players"
 	"github.com/hayasedb/hayase-cli/internal/providers"
 	"github.com/hayasedb/hayase-cli/internal/providers/aniworld"
@@ -19,13 +23,35 @@ import (
 	"github.com/charmbracelet/log"
 )
 
-type PlaybackEndedMsg struct{}
+type PlaybackEndedMsg struct {
+	ID     int
+	Result players.Result
+	Err    error
+	Next   *playback.Prefetch
+}
+
+type PlaybackStatusMsg struct {
+	Status string
+}
+
+type SkipTimesMsg struct {
+	ID        int
+	Intervals aniskip.Intervals
+}
+
+type PlayerStatusMsg struct {
+	ID     int
+	Status players.Status
+}
 
 type Model struct {
 	state          *navigation.State
 	provider       providers.Provider
 	playerRegistry *players.Registry
 	config         *storage.Config
+	extractors     *extractors.System
+	progress       *storage.ProgressStore
+	skipTimes      *aniskip.Client
 	animeView      *views.AnimeView
 	seasonView     *views.SeasonView
 	episodeView    *views.EpisodeView
@@ -33,14 +59,22 @@ type Model struct {
 	ctx            context.Context
 	cancelFunc     context.CancelFunc
 	playbackCancel context.CancelFunc
+	playbackID     int
+	controls       chan players.Control
+	next           *playback.Prefetch
+	updates        chan tea.Msg
+	statuses       chan PlaybackStatusMsg
+	playerStatuses chan PlayerStatusMsg
 }
 
 func NewModel(
 	ctx context.Context,
 	cancelFunc context.CancelFunc,
 	provider providers.Provider,
+	extractorSystem *extractors.System,
 	playerRegistry *players.Registry,
 	config *storage.Config,
+	progress *storage.ProgressStore,
 ) Model {
 	state := navigation.NewState()
 
@@ -49,19 +83,25 @@ func NewModel(
 		provider:       provider,
 		playerRegistry: playerRegistry,
 		config:         config,
+		extractors:     extractorSystem,
+		progress:       progress,
+		skipTimes:      aniskip.New(config.GetString("aniskip.url")),
 		animeView:      views.NewAnimeView(state, provider, config),
 		seasonView:     views.NewSeasonView(state, provider),
 		episodeView:    views.NewEpisodeView(state, provider),
-		playerView:     views.NewPlayerView(state, provider),
+		playerView:     views.NewPlayerView(state, provider, progress),
 		ctx:            ctx,
 		cancelFunc:     cancelFunc,
+		updates:        make(chan tea.Msg, 16),
+		statuses:       make(chan PlaybackStatusMsg, 1),
+		playerStatuses: make(chan PlayerStatusMsg, 1),
 	}
 
 	return model
 }
 
 func (m Model) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, m.waitForUpdate())
 }
 
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -87,11 +127,71 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 
 	case views.PlayEpisodeMsg:
-		return m, m.handlePlayback(msg.Anime, msg.Episode)
+		if msg.Prefetch == nil && m.next.Matches(msg.Anime, msg.Episode) {
+			msg.Prefetch = m.next
+		}
+		if m.next == msg.Prefetch {
+			m.next = nil
+		}
+		m.prefetchNext(msg.Anime, msg.Episode)
+
+		if m.playbackCancel != nil {
+			m.playbackCancel()
+		}
+		ctx, cancel := context.WithCancel(m.ctx)
+		m.playbackCancel = cancel
+		m.playbackID++
+		m.controls = make(chan players.Control, 8)
+		play := m.handlePlayback(ctx, m.playbackID, m.controls, msg, m.next)
+		if player, err := m.playerRegistry.GetDefault(); err == nil && players.UsesTerminal(player) {
+			log.Debug("Releasing the terminal for terminal video output")
+			terminal := &terminalPlayback{play: play}
+			return m, tea.Exec(terminal, terminal.result)
+		}
+		return m, play
+
+	case views.PlayerControlMsg:
+		select {
+		case m.controls <- msg.Control:
+		default:
+			log.Debug("Dropping player control", "control", msg.Control.Command.String())
+		}
+		return m, nil
+
+	case PlaybackStatusMsg:
+		m.playerView.SetStatus(msg.Status)
+		return m, m.waitForUpdate()
+
+	case PlayerStatusMsg:
+		if msg.ID == m.playbackID {
+			return m, tea.Batch(m.playerView.SetPlayback(msg.Status), m.waitForUpdate())
+		}
+		return m, m.waitForUpdate()
+
+	case SkipTimesMsg:
+		if msg.ID == m.playbackID {
+			m.playerView.SetSkips(msg.Intervals, m.config.GetSkipMode())
+		}
+		return m, m.waitForUpdate()
 
 	case PlaybackEndedMsg:
+		if msg.ID != m.playbackID {
+			log.Debug("Ignoring end of superseded playback", "id", msg.ID)
+			return m, nil
+		}
+		if msg.Next != nil && msg.Err == nil && m.state.GetCurrentView() == navigation.PlayerView {
+			m.episodeView.SetNotice(msg.Result.String())
+			return m, m.playerView.StartCountdown(msg.Result, msg.Next, m.config.GetBingeCountdown())
+		}
+
 		if m.state.GetCurrentView() == navigation.PlayerView {
 			m.state.NavigateBack()
+			m.stopPlayback()
+		}
+		if msg.Err != nil {
+			m.episodeView.SetNotice(fmt.Sprintf("Playback failed: %v", msg.Err))
+		} else if msg.Result != (players.Result{}) {
+			m.episodeView.SetNotice(msg.Result.String())
 		}
 		return m, nil
 	}
@@ -109,13 +209,16 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.episodeView, cmd = m.episodeView.Update(msg)
 	case navigation.PlayerView:
 		m.playerView, cmd = m.playerView.Update(msg)
-		if m.state.GetCurrentView() != navigation.PlayerView && m.playbackCancel != nil {
-			m.playbackCancel()
-			m.playbackCancel = nil
+		if m.state.GetCurrentView() != navigation.PlayerView {
+			m.stopPlayback()
 		}
 	}
 
 	if m.state.GetCurrentView() != previousView {
+		if m.state.GetCurrentView() == navigation.AnimeView {
+			m.next.Cancel()
+			m.next = nil
+		}
 		cmd = tea.Batch(cmd, m.handleViewTransition(m.state.GetCurrentView()))
 	}
 
@@ -137,24 +240,6 @@ func (m Model) handleViewTransition(to navigation.ViewState) tea.Cmd {
 	return nil
 }
 
-func filterAvailableProviders(providers map[string]map[models.Language]string) map[string]map[models.Language]string {
-	extractorSystem := extractors.NewSystem(nil)
-	filtered := make(map[string]map[models.Language]string)
-
-	supportedNames := make(map[string]bool)
-	for _, extractor := range extractorSystem.GetExtractors() {
-		supportedNames[extractor.Name()] = true
-	}
-
-	for providerName, languages := range providers {
-		if supportedNames[providerName] {
-			filtered[providerName] = languages
-		}
-	}
-
-	return filtered
-}
-
 func (m Model) View() string {
 	if m.state.IsQuitting() {
 		return "\n  Goodbye!\n\n"
@@ -174,109 +259,122 @@ func (m Model) View() string {
 	}
 }
 
-func (m *Model) handlePlayback(anime *models.Anime, episode *models.Episode) tea.Cmd {
+func (m *Model) handlePlayback(ctx context.Context, id int, controls <-chan players.Control, msg views.PlayEpisodeMsg, next *playback.Prefetch) tea.Cmd {
+	anime, episode, start, prefetched := msg.Anime, msg.Episode, msg.Start, msg.Prefetch
+
 	return func() tea.Msg {
 		log.Info("Starting playback",
 			"anime", anime.Title,
 			"season", episode.Season,
-			"episode", episode.Episode)
+			"episode", episode.Episode,
+			"start", players.FormatPosition(start))
 
-		if m.playbackCancel != nil {
-			m.playbackCancel()
+		failed := func(message string, err error) tea.Msg {
+			if ctx.Err() != nil {
+				log.Debug("Playback superseded", "episode", episode.String())
+				return PlaybackEndedMsg{ID: id}
+			}
+			log.Error(message, "error", err)
+			return PlaybackEndedMsg{ID: id, Err: err}
 		}
 
-		playbackCtx, cancel := context.WithCancel(m.ctx)
-		m.playbackCancel = cancel
-		ctx := playbackCtx
-		maxRetries := 3
+		m.reportStatus("Loading episode...")
 
-		episodeDetails, err := m.provider.GetEpisode(ctx, anime, episode.Season, episode.Episode)
+		episodeDetails, err := playback.LoadEpisode(ctx, m.provider, anime, episode.Season, episode.Episode, prefetched)
 		if err != nil {
-			log.Error("Failed to get episode details", "error", err)
-			return tea.Quit()
+			return failed("Failed to get episode details", err)
+		}
+
+		if m.config.GetSkipMode() != "off" {
+			go func() {
+				if intervals := playback.LoadSkipTimes(ctx, m.skipTimes, anime, episodeDetails); len(intervals) > 0 {
+					m.send(ctx, SkipTimesMsg{ID: id, Intervals: intervals})
+				}
+			}()
 		}
 
 		player, err := m.playerRegistry.GetDefault()
 		if err != nil {
-			log.Error("No player available", "error", err)
-			return tea.Quit()
+			return failed("No player available", fmt.Errorf("no player available: %w", err))
 		}
 
-		preferredLang := m.config.GetLanguage()
-
-		availableProviders := filterAvailableProviders(episodeDetails.Providers)
-
-		var redirectURL string
-		var providerName string
-		var found bool
-
-		for name, languages := range availableProviders {
-			if url, exists := languages[preferredLang]; exists {
-				redirectURL = url
-				providerName = name
-				found = true
-				break
-			}
+		candidates := m.extractors.Rank(episodeDetails.Providers, m.config.GetLanguagePriority())
+		if len(candidates) == 0 {
+			return failed("No stream providers available", errors.New("no stream providers available"))
 		}
 
-		if !found {
-			for name, languages := range availableProviders {
-				for _, url := range languages {
-					redirectURL = url
-					providerName = name
-					found = true
-					break
-				}
-				if found {
-					break
-				}
-			}
+		resolver, err := m.resolver()
+		if err != nil {
+			return failed("Failed to set up stream resolution", err)
 		}
-
-		if !found {
-			log.Error("No stream providers available")
-			return tea.Quit()
+		follow := resolver.Follow
+
+		opts := players.Options{
+			Title:  fmt.Sprintf("%s - %s", anime.Title, episode.String()),
+			Start:  start,
+			Tracks: m.config.GetTrackPreference(anime),
+			Status: func(status players.Status) {
+				sendLatest(m.playerStatuses, PlayerStatusMsg{ID: id, Status: status})
+			},
+			Controls: controls,
 		}
-
-		aniWorldProvider, ok := m.provider.(*aniworld.Provider)
-		if !ok {
-			log.Error("Provider is not AniWorld provider")
-			return tea.Quit()
+		progress := func(p extractors.Progress) {
+			m.reportStatus(p.String())
 		}
 
-		title := fmt.Sprintf("%s - %s", anime.Title, episode.String())
-
-		for attempt := 1; attempt <= maxRetries; attempt++ {
-			log.Info("Extracting stream URL",
-				"provider", providerName,
-				"attempt", attempt)
+		streamURL, candidate, rest, prefetchedStream := prefetched.Stream(ctx)
+		prefetched.Cancel()
+		if prefetchedStream {
+			log.Info("Using prefetched stream", "hoster", candidate.Hoster, "expires_at", streamURL.ExpiresAt)
+			candidates = rest
+		}
 
-			streamURL, err := aniWorldProvider.GetClient().ExtractStreamURL(ctx, redirectURL)
-			if err != nil {
-				log.Warn("Failed to extract stream URL", "error", err, "attempt", attempt)
-				if attempt == maxRetries {
-					log.Error("Failed to extract stream URL after all retries")
-					return tea.Quit()
+		for streamURL != nil || len(candidates) > 0 {
+			if streamURL == nil {
+				var index int
+				streamURL, index, err = m.extractors.Resolve(ctx, candidates, follow, progress)
+				if err != nil {
+					return failed("Failed to resolve stream", err)
 				}
-				continue
-			}
 
-			if streamURL.IsExpired() {
-				log.Warn("Stream URL is expired", "attempt", attempt)
-				if attempt == maxRetries {
-					log.Error("Stream URL still expired after all retries")
-					return tea.Quit()
-				}
-				continue
+				candidate = candidates[index]
+				candidates = candidates[index+1:]
 			}
 
 			log.Info("Starting playback",
+				"hoster", candidate.Hoster,
+				"language", candidate.Language.String(),
 				"quality", streamURL.Quality.String(),
 				"player", player.Name())
 
-			playbackErr := player.Play(ctx, streamURL, title)
+			m.reportStatus(fmt.Sprintf("Playing via %s, %s with %s", candidate.String(), streamURL.Quality.String(), player.Name()))
+
+			source := playback.NewSource(resolver, candidate, candidates, progress)
+			opts.Refresh = func(ctx context.Context) (*models.StreamURL, error) {
+				m.reportStatus("Stream interrupted, extracting again...")
+				refreshed, err := source.Refresh(ctx)
+				if err == nil {
+					current, _ := source.Current()
+					m.reportStatus(fmt.Sprintf("Playing via %s, %s with %s", current.String(), refreshed.Quality.String(), player.Name()))
+				}
+				return refreshed, err
+			}
+
+			result, playbackErr := player.Play(ctx, streamURL, opts)
+			streamURL = nil
+			candidate, candidates = source.Current()
+			log.Info("Playback ended",
+				"reason", result.Reason.String(),
+				"position", players.FormatPosition(result.Position),
+				"duration", players.FormatPosition(result.Duration),
+				"percent", fmt.Sprintf("%.0f", result.Percent()))
+			m.recordProgress(anime, episodeDetails, result)
+
 			if playbackErr == nil {
-				return PlaybackEndedMsg{}
+				if result.Reason == players.EndEOF && m.config.GetBinge() && next != nil {
+					return PlaybackEndedMsg{ID: id, Result: result, Next: next}
+				}
+				return PlaybackEndedMsg{ID: id, Result: result}
 			}
 
 			errorStr := playbackErr.Error()
@@ -284,15 +382,130 @@ func (m *Model) handlePlayback(anime *models.Anime, episode *models.Episode) tea
 				strings.Contains(errorStr, "403") ||
 				strings.Contains(errorStr, "Forbidden")
 
-			if isRetryable && attempt < maxRetries {
-				log.Warn("Playback failed with retryable error", "error", playbackErr, "attempt", attempt)
+			if isRetryable && len(candidates) > 0 {
+				log.Warn("Playback failed with retryable error", "error", playbackErr, "hoster", candidate.Hoster)
+				m.reportStatus(fmt.Sprintf("%s failed during playback, trying %s", candidate.Hoster, candidates[0].Hoster))
+				opts.Start = max(opts.Start, result.Position)
 				continue
 			}
 
 			log.Error("Failed to play episode", "error", playbackErr)
-			return PlaybackEndedMsg{}
+			return PlaybackEndedMsg{ID: id, Result: result, Err: playbackErr}
 		}
 
-		return PlaybackEndedMsg{}
+		return PlaybackEndedMsg{ID: id}
+	}
+}
+
+type terminalPlayback struct {
+	play tea.Cmd
+	msg  tea.Msg
+}
+
+func (t *terminalPlayback) Run() error {
+	t.msg = t.play()
+	return nil
+}
+
+func (t *terminalPlayback) SetStdin(io.Reader)  {}
+func (t *terminalPlayback) SetStdout(io.Writer) {}
+func (t *terminalPlayback) SetStderr(io.Writer) {}
+
+func (t *terminalPlayback) result(error) tea.Msg {
+	return t.msg
+}
+
+func (m *Model) stopPlayback() {
+	if m.playbackCancel != nil {
+		m.playbackCancel()
+		m.playbackCancel = nil
+	}
+}
+
+func (m *Model) resolver() (playback.Resolver, error) {
+	aniWorldProvider, ok := m.provider.(*aniworld.Provider)
+	if !ok {
+		return playback.Resolver{}, fmt.Errorf("provider is not AniWorld provider")
+	}
+
+	return playback.Resolver{
+		Provider:   m.provider,
+		Extractors: m.extractors,
+		Follow:     aniWorldProvider.GetClient().FollowRedirect,
+		Languages:  m.config.GetLanguagePriority(),
+	}, nil
+}
+
+func (m *Model) prefetchNext(anime *models.Anime, episode *models.Episode) {
+	var nextEpisode *models.Episode
+	if m.config.GetBinge() || m.config.GetPrefetch() {
+		nextEpisode = anime.NextEpisode(episode.Season, episode.Episode)
+	}
+	if nextEpisode != nil && m.next.Matches(anime, nextEpisode) {
+		return
+	}
+
+	m.next.Cancel()
+	m.next = nil
+	if nextEpisode == nil {
+		return
+	}
+
+	resolver, err := m.resolver()
+	if err != nil {
+		log.Debug("Not prefetching next episode", "error", err)
+		return
+	}
+	m.next = playback.StartPrefetch(m.ctx, resolver, anime, nextEpisode)
+}
+
+func (m *Model) recordProgress(anime *models.Anime, episode *models.Episode, result players.Result) {
+	if m.progress == nil || (result.Position <= 0 && result.Reason != players.EndEOF) {
+		return
+	}
+
+	m.progress.Record(anime, episode, result.Position, result.Duration, result.Reason == players.EndEOF)
+	if err := m.progress.Save(); err != nil {
+		log.Warn("Failed to save watch progress", "error", err)
+	}
+}
+
+func (m *Model) reportStatus(status string) {
+	sendLatest(m.statuses, PlaybackStatusMsg{Status: status})
+}
+
+func (m *Model) send(ctx context.Context, msg tea.Msg) {
+	select {
+	case m.updates <- msg:
+	case <-ctx.Done():
+		log.Debug("Dropping playback update", "update", fmt.Sprintf("%T", msg))
+	}
+}
+
+func sendLatest[T any](ch chan T, msg T) {
+	for {
+		select {
+		case ch <- msg:
+			return
+		default:
+		}
+
+		select {
+		case <-ch:
+		default:
+		}
+	}
+}
+
+func (m *Model) waitForUpdate() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case msg := <-m.updates:
+			return msg
+		case msg := <-m.statuses:
+			return msg
+		case msg := <-m.playerStatuses:
+			return msg
+		}
 	}
 }
@@ -0,0 +1,24 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	progressFilledStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	progressEmptyStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+)
+
+func ProgressBar(percent float64, width int) string {
+	if width <= 0 {
+		return ""
+	}
+
+	filled := int(percent / 100 * float64(width))
+	filled = min(max(filled, 0), width)
+
+	return progressFilledStyle.Render(strings.Repeat("█", filled)) +
+		progressEmptyStyle.Render(strings.Repeat("░", width-filled))
+}
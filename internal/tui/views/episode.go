@@ -43,6 +43,7 @@ type EpisodeView struct {
 	width    int
 	height   int
 	footer   *ui.Footer
+	notice   string
 }
 
 func NewEpisodeView(state *navigation.State, provider providers.Provider) *EpisodeView {
@@ -99,6 +100,7 @@ func (v *EpisodeView) handleKeys(msg tea.KeyMsg) (*EpisodeView, tea.Cmd) {
 
 func (v *EpisodeView) handleSelection() (*EpisodeView, tea.Cmd) {
 	if item, ok := v.list.SelectedItem().(episodeItem); ok {
+		v.notice = ""
 		v.state.SetEpisode(item.episode)
 		v.state.NavigateForward()
 	}
@@ -141,14 +143,26 @@ func (v *EpisodeView) setListTitle(animeTitle string, seasonNum int) {
 	}
 }
 
+func (v *EpisodeView) SetNotice(notice string) {
+	v.notice = notice
+}
+
 func (v *EpisodeView) View() string {
 	v.footer.SetKeys(ui.EpisodeNavigationKeys())
 	footerView := v.footer.View()
 
+	noticeView := ""
+	if v.notice != "" {
+		noticeView = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("241")).
+			MarginLeft(2).
+			Render(v.notice)
+	}
+
 	var content string
 	switch {
 	case len(v.list.Items()) > 0:
-		v.list.SetHeight(v.height - lipgloss.Height(footerView) - 1)
+		v.list.SetHeight(v.height - lipgloss.Height(footerView) - lipgloss.Height(noticeView) - 1)
 		content = lipgloss.NewStyle().MarginTop(1).Render(v.list.View())
 	default:
 		content = lipgloss.NewStyle().
@@ -158,5 +172,8 @@ func (v *EpisodeView) View() string {
 			Render("No episodes available")
 	}
 
+	if noticeView != "" {
+		return lipgloss.JoinVertical(lipgloss.Left, content, noticeView, footerView)
+	}
 	return lipgloss.JoinVertical(lipgloss.Left, content, footerView)
 }
@@ -2,10 +2,16 @@ package views
 
 import (
 	"fmt"
+	"time"
 
+	"github.com/hayasedb/hayase-cli/internal/aniskip"
 	"github.com/hayasedb/hayase-cli/internal/models"
+	"github.com/hayasedb/hayase-cli/internal/playback"
+	"github.com/hayasedb/hayase-cli/internal/players"
 	"github.com/hayasedb/hayase-cli/internal/providers"
+	"github.com/hayasedb/hayase-cli/internal/storage"
 	"github.com/hayasedb/hayase-cli/internal/tui/navigation"
+	"github.com/hayasedb/hayase-cli/internal/tui/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -14,49 +20,293 @@ type PlayEpisodeMsg struct {
 	Anime    *models.Anime
 	Episode  *models.Episode
 	Provider providers.Provider
+	Start    time.Duration
+	Prefetch *playback.Prefetch
+}
+
+type PlayerControlMsg struct {
+	Control players.Control
+}
+
+const (
+	shortSeek = 10 * time.Second
+	longSeek  = 85 * time.Second
+)
+
+type bingeTickMsg struct {
+	id int
 }
 
 type PlayerView struct {
-	state    *navigation.State
-	provider providers.Provider
+	state       *navigation.State
+	provider    providers.Provider
+	progress    *storage.ProgressStore
+	status      string
+	prompting   bool
+	resumeAt    time.Duration
+	startOver   bool
+	next        *playback.Prefetch
+	remaining   time.Duration
+	countdownID int
+	playback    players.Status
+	playing     bool
+	skipper     *aniskip.Skipper
+	skipMode    string
+	skipIndex   int
+	skip        *aniskip.Interval
 }
 
-func NewPlayerView(state *navigation.State, provider providers.Provider) *PlayerView {
+func NewPlayerView(state *navigation.State, provider providers.Provider, progress *storage.ProgressStore) *PlayerView {
 	return &PlayerView{
 		state:    state,
 		provider: provider,
+		progress: progress,
 	}
 }
 
 func (v *PlayerView) Init() tea.Cmd {
 	anime := v.state.GetAnime()
 	episode := v.state.GetEpisode()
-	if anime != nil && episode != nil {
-		return v.triggerPlayback(anime, episode)
+	if anime == nil || episode == nil {
+		return nil
 	}
-	return nil
+
+	v.prompting = false
+	v.startOver = false
+	v.playing = false
+	v.stopCountdown()
+
+	if v.progress != nil {
+		if position, ok := v.progress.Resume(anime, episode); ok {
+			v.prompting = true
+			v.resumeAt = position
+			v.status = ""
+			return nil
+		}
+	}
+
+	return v.start(anime, episode, 0)
 }
 
-func (v *PlayerView) triggerPlayback(anime *models.Anime, episode *models.Episode) tea.Cmd {
+func (v *PlayerView) start(anime *models.Anime, episode *models.Episode, position time.Duration) tea.Cmd {
+	v.prompting = false
+	v.playing = false
+	v.clearSkips()
+	v.status = "Resolving stream..."
+	return v.triggerPlayback(anime, episode, position, nil)
+}
+
+func (v *PlayerView) triggerPlayback(anime *models.Anime, episode *models.Episode, position time.Duration, prefetch *playback.Prefetch) tea.Cmd {
 	return func() tea.Msg {
 		return PlayEpisodeMsg{
 			Anime:    anime,
 			Episode:  episode,
 			Provider: v.provider,
+			Start:    position,
+			Prefetch: prefetch,
 		}
 	}
 }
 
+func (v *PlayerView) StartCountdown(result players.Result, next *playback.Prefetch, wait time.Duration) tea.Cmd {
+	v.stopCountdown()
+	v.next = next
+	v.remaining = wait
+	v.status = result.String()
+
+	if wait <= 0 {
+		return v.playNext()
+	}
+	return v.tick()
+}
+
+func (v *PlayerView) tick() tea.Cmd {
+	id := v.countdownID
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return bingeTickMsg{id: id}
+	})
+}
+
+func (v *PlayerView) stopCountdown() {
+	v.next = nil
+	v.remaining = 0
+	v.countdownID++
+}
+
+func (v *PlayerView) playNext() tea.Cmd {
+	next := v.next
+	v.stopCountdown()
+
+	v.state.SetSeason(next.Episode.Season)
+	v.state.SetEpisode(next.Episode)
+
+	var position time.Duration
+	if v.progress != nil {
+		if resumeAt, ok := v.progress.Resume(next.Anime, next.Episode); ok {
+			position = resumeAt
+		}
+	}
+
+	v.prompting = false
+	v.playing = false
+	v.clearSkips()
+	v.status = "Resolving stream..."
+	return v.triggerPlayback(next.Anime, next.Episode, position, next)
+}
+
+func (v *PlayerView) SetStatus(status string) {
+	v.status = status
+}
+
+func (v *PlayerView) SetPlayback(status players.Status) tea.Cmd {
+	v.playback = status
+	v.playing = true
+
+	index, interval, ok := v.skipper.Pending(status.Position)
+	if !ok {
+		v.skip = nil
+		return nil
+	}
+
+	if v.skipMode == "auto" {
+		v.skipper.Handle(index)
+		v.status = fmt.Sprintf("Skipped %s", interval.Label())
+		return v.control(players.SeekTo(interval.End))
+	}
+
+	v.skipIndex, v.skip = index, &interval
+	return nil
+}
+
+func (v *PlayerView) SetSkips(intervals aniskip.Intervals, mode string) {
+	v.skipper = aniskip.NewSkipper(intervals)
+	v.skipMode = mode
+	v.skip = nil
+}
+
+func (v *PlayerView) clearSkips() {
+	v.skipper = nil
+	v.skip = nil
+}
+
+func (v *PlayerView) skipCurrent() tea.Cmd {
+	interval := *v.skip
+	v.skipper.Handle(v.skipIndex)
+	v.skip = nil
+	v.status = fmt.Sprintf("Skipped %s", interval.Label())
+	return v.control(players.SeekTo(interval.End))
+}
+
+func (v *PlayerView) jump(episode *models.Episode) tea.Cmd {
+	anime := v.state.GetAnime()
+	if anime == nil || episode == nil {
+		return nil
+	}
+
+	v.state.SetSeason(episode.Season)
+	v.state.SetEpisode(episode)
+
+	var position time.Duration
+	if v.progress != nil {
+		if resumeAt, ok := v.progress.Resume(anime, episode); ok {
+			position = resumeAt
+		}
+	}
+
+	return v.start(anime, episode, position)
+}
+
+func (v *PlayerView) control(control players.Control) tea.Cmd {
+	if !v.playing {
+		return nil
+	}
+	return func() tea.Msg {
+		return PlayerControlMsg{Control: control}
+	}
+}
+
 func (v *PlayerView) Update(msg tea.Msg) (*PlayerView, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		return v.handleKeys(msg)
+
+	case bingeTickMsg:
+		if msg.id != v.countdownID || v.next == nil {
+			return v, nil
+		}
+		v.remaining -= time.Second
+		if v.remaining <= 0 {
+			return v, v.playNext()
+		}
+		return v, v.tick()
 	}
 
 	return v, nil
 }
 
 func (v *PlayerView) handleKeys(msg tea.KeyMsg) (*PlayerView, tea.Cmd) {
+	if v.next != nil {
+		switch msg.String() {
+		case "enter", "n":
+			return v, v.playNext()
+		case "c", "esc":
+			v.stopCountdown()
+			v.state.NavigateBack()
+			return v, nil
+		case "q":
+			v.stopCountdown()
+		}
+	}
+
+	if v.prompting {
+		switch msg.String() {
+		case "up", "down", "k", "j", "tab":
+			v.startOver = !v.startOver
+			return v, nil
+		case "r":
+			return v, v.start(v.state.GetAnime(), v.state.GetEpisode(), v.resumeAt)
+		case "s":
+			return v, v.start(v.state.GetAnime(), v.state.GetEpisode(), 0)
+		case "enter":
+			position := v.resumeAt
+			if v.startOver {
+				position = 0
+			}
+			return v, v.start(v.state.GetAnime(), v.state.GetEpisode(), position)
+		}
+	}
+
+	if !v.prompting {
+		switch msg.String() {
+		case "enter":
+			if v.skip != nil {
+				return v, v.skipCurrent()
+			}
+		case " ":
+			return v, v.control(players.Control{Command: players.CommandTogglePause})
+		case "left", "h":
+			return v, v.control(players.Seek(-shortSeek))
+		case "right", "l":
+			return v, v.control(players.Seek(shortSeek))
+		case "shift+left", "H":
+			return v, v.control(players.Seek(-longSeek))
+		case "shift+right", "L":
+			return v, v.control(players.Seek(longSeek))
+		case "s":
+			return v, v.control(players.Control{Command: players.CommandCycleSubtitle})
+		case "a":
+			return v, v.control(players.Control{Command: players.CommandCycleAudio})
+		case "n":
+			if episode := v.state.GetEpisode(); episode != nil {
+				return v, v.jump(v.state.GetAnime().NextEpisode(episode.Season, episode.Episode))
+			}
+		case "p":
+			if episode := v.state.GetEpisode(); episode != nil {
+				return v, v.jump(v.state.GetAnime().PreviousEpisode(episode.Season, episode.Episode))
+			}
+		}
+	}
+
 	switch msg.String() {
 	case "ctrl+c", "q":
 		v.state.SetQuitting(true)
@@ -87,6 +337,31 @@ func (v *PlayerView) View() string {
 		episodeInfo = fmt.Sprintf("Episode %d: %s", episode.Episode, episode.Title)
 	}
 
-	return fmt.Sprintf("Now Playing: %s - %s %s\nStatus: MPV Running\n\nPress Esc to go back, q to quit",
-		anime.Title, seasonText, episodeInfo)
+	if v.prompting {
+		resume, startOver := "> ", "  "
+		if v.startOver {
+			resume, startOver = "  ", "> "
+		}
+		return fmt.Sprintf("Now Playing: %s - %s %s\n\n%sResume from %s\n%sStart over\n\nPress Enter to confirm (r resume, s start over), Esc to go back, q to quit",
+			anime.Title, seasonText, episodeInfo, resume, players.FormatPosition(v.resumeAt), startOver)
+	}
+
+	if v.next != nil {
+		return fmt.Sprintf("Now Playing: %s - %s %s\nStatus: %s\n\nUp next: %s in %ds\n\nPress Enter to play now, c or Esc to cancel, q to quit",
+			anime.Title, seasonText, episodeInfo, v.status, v.next.Episode.String(), int(v.remaining.Seconds()))
+	}
+
+	if v.playing {
+		width, _ := v.state.GetDimensions()
+		barWidth := min(max(width-4, 10), 60)
+		skipLine := ""
+		if v.skip != nil {
+			skipLine = fmt.Sprintf("\nPress Enter to skip the %s (until %s)", v.skip.Label(), players.FormatPosition(v.skip.End))
+		}
+		return fmt.Sprintf("Now Playing: %s - %s %s\nStatus: %s\n\n%s\n%s%s\n\nspace pause, ←/→ seek 10s, shift+←/→ seek 85s, n/p next/previous, s subtitles, a audio\nPress Esc to go back, q to quit",
+			anime.Title, seasonText, episodeInfo, v.status, ui.ProgressBar(v.playback.Percent(), barWidth), v.playback.String(), skipLine)
+	}
+
+	return fmt.Sprintf("Now Playing: %s - %s %s\nStatus: %s\n\nn/p next/previous episode\nPress Esc to go back, q to quit",
+		anime.Title, seasonText, episodeInfo, v.status)
 }